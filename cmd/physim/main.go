@@ -0,0 +1,201 @@
+// Command physim loads a scene file, steps it and prints stats, a trajectory or the final state hash.
+//
+//	physim -steps 600 -dt 0.016666 -stats scene.json
+//	physim -steps 600 -trajectory out.csv scene.json
+//	physim -steps 600 -hash scene.json
+//	physim -steps 600 -expect 9f3a61c2d4e5b607 scene.json
+//	physim -steps 6000 -cpuprofile cpu.out scene.json
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+
+	physics "github.com/cs8425/go-physics"
+)
+
+var (
+	errHashMismatch = errors.New("final state hash does not match -expect")
+	errUsage = errors.New("usage error")
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if err == errUsage {
+			os.Exit(2) // the flag package or run already printed the problem and usage
+		}
+		fmt.Fprintln(os.Stderr, "physim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) (err error) {
+	fs := flag.NewFlagSet("physim", flag.ContinueOnError)
+	steps := fs.Int("steps", 60, "number of steps")
+	dt := fs.Float64("dt", 1.0 / 60, "time step in seconds")
+	stats := fs.Bool("stats", false, "print time, kinetic energy, max speed and bounds every step")
+	trajectory := fs.String("trajectory", "", "write the trajectory to this file, - for stdout")
+	format := fs.String("format", "", "trajectory format: csv or jsonl, by default from the file extension")
+	hash := fs.Bool("hash", false, "print the final state hash")
+	expect := fs.String("expect", "", "fail unless the final state hash equals this hex value")
+	cpuprofile := fs.String("cpuprofile", "", "write a CPU profile of the stepping to this file")
+	memprofile := fs.String("memprofile", "", "write an allocation profile to this file when done")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: physim [flags] scene.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil // -h, usage is already printed
+		}
+		return errUsage // the error and usage are already printed
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(fs.Output(), "need exactly one scene file")
+		fs.Usage()
+		return errUsage
+	}
+	if *steps < 0 || *dt <= 0 {
+		return fmt.Errorf("need -steps >= 0 and -dt > 0")
+	}
+
+	var want uint64
+	if *expect != "" {
+		var err error
+		want, err = strconv.ParseUint(strings.TrimPrefix(*expect, "0x"), 16, 64)
+		if err != nil {
+			return fmt.Errorf("bad -expect: %v", err)
+		}
+	}
+
+	sb, err := physics.LoadSceneFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer func() {
+		if ferr := out.Flush(); err == nil {
+			err = ferr
+		}
+	}()
+
+	var rec *physics.Recorder
+	var file *os.File // trajectory file, closed explicitly so write errors are reported
+	if *trajectory != "" {
+		tf, err := trajectoryFormat(*format, *trajectory)
+		if err != nil {
+			return err
+		}
+		w := io.Writer(out)
+		if *trajectory != "-" {
+			file, err = os.Create(*trajectory)
+			if err != nil {
+				return err
+			}
+			defer file.Close() // only for early returns, a second Close is harmless
+			w = file
+		}
+		rec = physics.NewRecorder(w, tf)
+	}
+
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if *stats {
+		fmt.Fprintln(out, "step\ttime\tkinetic\tmaxSpeed\tminX\tminY\tminZ\tmaxX\tmaxY\tmaxZ")
+	}
+	for i := 0; i < *steps; i++ {
+		sb.Step(physics.Number(*dt))
+		if *stats {
+			printStats(out, i, sb)
+		}
+		if rec != nil {
+			if err := rec.Record(sb); err != nil {
+				return err
+			}
+		}
+	}
+	if rec != nil {
+		if err := rec.Flush(); err != nil {
+			return err
+		}
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+
+	if *memprofile != "" {
+		f, err := os.Create(*memprofile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+			return err
+		}
+	}
+
+	sum := sb.StateHash(physics.NewStateHash()).Sum64()
+	if *hash {
+		fmt.Fprintf(out, "%016x\n", sum)
+	}
+	if *expect != "" && sum != want {
+		fmt.Fprintf(out, "got %016x, want %016x\n", sum, want)
+		return errHashMismatch
+	}
+	return nil
+}
+
+func trajectoryFormat(name, path string) (physics.TrajectoryFormat, error) {
+	if name == "" {
+		if strings.HasSuffix(path, ".jsonl") || strings.HasSuffix(path, ".ndjson") {
+			return physics.TrajectoryJSONL, nil
+		}
+		return physics.TrajectoryCSV, nil
+	}
+	switch name {
+	case "csv":
+		return physics.TrajectoryCSV, nil
+	case "jsonl":
+		return physics.TrajectoryJSONL, nil
+	}
+	return 0, fmt.Errorf("unknown trajectory format %q", name)
+}
+
+func printStats(w io.Writer, step int, sb *physics.SoftBody) {
+	var kinetic, maxSpeed physics.Number
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		v2 := p.Velocity.LengthSquared()
+		if p.InvMass > 0 && !p.Pinned {
+			kinetic += 0.5 * v2 / p.InvMass
+		}
+		maxSpeed = physics.Number(math.Max(float64(maxSpeed), math.Sqrt(float64(v2))))
+	}
+	var min, max physics.Vec3
+	sb.AABB(&min, &max)
+	fmt.Fprintf(w, "%d\t%g\t%g\t%g\t%g\t%g\t%g\t%g\t%g\t%g\n", step, sb.Time, kinetic, maxSpeed,
+		min[0], min[1], min[2], max[0], max[1], max[2])
+}
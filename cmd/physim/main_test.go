@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testScene = `{
+	"gravity": {"x": 0, "y": -9.82, "z": 0},
+	"ropes": [
+		{"from": [0, 4, 0], "to": [2, 4, 0], "segments": 4, "mass": 0.5, "stiffness": 1, "attachFrom": true}
+	],
+	"colliders": [
+		{"type": "plane", "normal": [0, 1, 0]}
+	]
+}`
+
+func TestRun(t *testing.T) {
+
+	dir := t.TempDir()
+	scene := filepath.Join(dir, "rope.json")
+	if err := os.WriteFile(scene, []byte(testScene), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{ "-steps", "30", "-hash", scene }, &out); err != nil {
+		t.Fatal(err)
+	}
+	hash := strings.TrimSpace(out.String())
+	if len(hash) != 16 {
+		t.Fatal("Error printing state hash, got ", hash)
+	}
+
+	out.Reset()
+	if err := run([]string{ "-steps", "30", "-expect", hash, scene }, &out); err != nil {
+		t.Error("same run should match its own hash, got ", err, out.String())
+	}
+	if err := run([]string{ "-steps", "31", "-expect", hash, scene }, &out); err != errHashMismatch {
+		t.Error("expected errHashMismatch, got ", err)
+	}
+
+	out.Reset()
+	if err := run([]string{ "-steps", "3", "-stats", scene }, &out); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "\n"); n != 4 {
+		t.Error("Error printing stats, got ", n, " lines")
+	}
+
+	traj := filepath.Join(dir, "rope.jsonl")
+	if err := run([]string{ "-steps", "3", "-trajectory", traj, scene }, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(traj)
+	if n := strings.Count(string(data), "\n"); n != 3 * 5 {
+		t.Error("Error writing trajectory, got ", n, " lines")
+	}
+
+	if err := run([]string{ "-format", "xml", "-trajectory", traj, scene }, &out); err == nil || !strings.Contains(fmt.Sprint(err), "xml") {
+		t.Error("unknown format should fail, got ", err)
+	}
+
+	if err := run([]string{ "-h" }, &out); err != nil {
+		t.Error("-h should not be an error, got ", err)
+	}
+	if err := run([]string{}, &out); err != errUsage {
+		t.Error("expected errUsage, got ", err)
+	}
+	if err := run([]string{ "-bogus", scene }, &out); err != errUsage {
+		t.Error("bad flag should be errUsage, got ", err)
+	}
+
+}
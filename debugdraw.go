@@ -0,0 +1,404 @@
+package physics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+type DebugColor uint32 // 0xRRGGBB
+
+const (
+	DebugRed DebugColor = 0xff0000
+	DebugGreen DebugColor = 0x00ff00
+	DebugBlue DebugColor = 0x0000ff
+	DebugYellow DebugColor = 0xffff00
+	DebugWhite DebugColor = 0xffffff
+	DebugGray DebugColor = 0x808080
+)
+
+/**
+ * Receives debug geometry in world space.
+ * @class DebugDrawer
+ */
+type DebugDrawer interface {
+	DrawLine(from, to *Vec3, color DebugColor)
+	DrawPoint(p *Vec3, color DebugColor)
+}
+
+/**
+ * Implemented by colliders that know how to draw themselves.
+ * @class DebugDrawable
+ */
+type DebugDrawable interface {
+	DebugDraw(d DebugDrawer)
+}
+
+/**
+ * Draw the axes of a transform: x red, y green, z blue.
+ * @method drawTransform
+ * @param {DebugDrawer} d
+ * @param {Transform} tf nil Pos and Rot are the origin and identity
+ * @param {Number} size Length of the axes
+ */
+func DrawTransform(d DebugDrawer, tf *Transform, size Number) {
+	colors := [3]DebugColor{ DebugRed, DebugGreen, DebugBlue }
+	pos, rot := tf.values()
+	var unit, axis, end Vec3
+	for i := 0; i < 3; i++ {
+		unit.Set(0, 0, 0)
+		unit[i] = size
+		rot.VMult(&unit, &axis)
+		pos.VAdd(&axis, &end)
+		d.DrawLine(&pos, &end, colors[i])
+	}
+}
+
+/**
+ * Draw the 12 edges of an axis aligned box.
+ * @method drawAABB
+ * @param {DebugDrawer} d
+ * @param {Vec3} min
+ * @param {Vec3} max
+ * @param {DebugColor} color
+ */
+func DrawAABB(d DebugDrawer, min, max *Vec3, color DebugColor) {
+	var corners [8]Vec3
+	for i := range corners {
+		for k := 0; k < 3; k++ {
+			if i & (1 << k) != 0 {
+				corners[i][k] = max[k]
+			} else {
+				corners[i][k] = min[k]
+			}
+		}
+	}
+	drawBoxEdges(d, &corners, color)
+}
+
+// corner i has max in axis k when bit k of i is set
+func drawBoxEdges(d DebugDrawer, corners *[8]Vec3, color DebugColor) {
+	for i := 0; i < 8; i++ {
+		for k := 0; k < 3; k++ {
+			if j := i | (1 << k); j != i {
+				d.DrawLine(&corners[i], &corners[j], color)
+			}
+		}
+	}
+}
+
+/**
+ * Draw a contact point and its normal.
+ * @method drawContact
+ * @param {DebugDrawer} d
+ * @param {Vec3} point
+ * @param {Vec3} normal
+ * @param {Number} length Length of the normal line
+ */
+func DrawContact(d DebugDrawer, point, normal *Vec3, length Number) {
+	var end Vec3
+	point.AddScaledVector(length, normal, &end)
+	d.DrawPoint(point, DebugYellow)
+	d.DrawLine(point, &end, DebugYellow)
+}
+
+// circle of radius r around center, in the plane spanned by u and v
+func drawCircle(d DebugDrawer, center, u, v *Vec3, r Number, color DebugColor) {
+	const segments = 24
+	var prev, cur Vec3
+	for i := 0; i <= segments; i++ {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / segments)
+		center.AddScaledVector(r * Number(c), u, &cur)
+		addScaled(&cur, r * Number(s), v)
+		if i > 0 {
+			d.DrawLine(&prev, &cur, color)
+		}
+		prev = cur
+	}
+}
+
+/**
+ * @method debugDraw
+ */
+func (c *PlaneCollider) DebugDraw(d DebugDrawer) {
+	var center, u, v, end Vec3
+	c.Normal.Scale(c.Constant, &center)
+	c.Normal.Tangents(&u, &v)
+	for i := -5; i <= 5; i++ {
+		var a, b Vec3
+		center.AddScaledVector(Number(i), &u, &a)
+		addScaled(&a, -5, &v)
+		a.AddScaledVector(10, &v, &b)
+		d.DrawLine(&a, &b, DebugGray)
+
+		center.AddScaledVector(Number(i), &v, &a)
+		addScaled(&a, -5, &u)
+		a.AddScaledVector(10, &u, &b)
+		d.DrawLine(&a, &b, DebugGray)
+	}
+	center.VAdd(&c.Normal, &end)
+	d.DrawLine(&center, &end, DebugWhite)
+}
+
+/**
+ * @method debugDraw
+ */
+func (c *SphereCollider) DebugDraw(d DebugDrawer) {
+	x, y, z := &Vec3{ 1, 0, 0 }, &Vec3{ 0, 1, 0 }, &Vec3{ 0, 0, 1 }
+	drawCircle(d, &c.Center, x, y, c.Radius, DebugGray)
+	drawCircle(d, &c.Center, y, z, c.Radius, DebugGray)
+	drawCircle(d, &c.Center, z, x, c.Radius, DebugGray)
+}
+
+/**
+ * @method debugDraw
+ */
+func (c *BoxCollider) DebugDraw(d DebugDrawer) {
+	var corners [8]Vec3
+	var local Vec3
+	pos, rot := c.Transform.values()
+	for i := range corners {
+		for k := 0; k < 3; k++ {
+			if i & (1 << k) != 0 {
+				local[k] = c.HalfExtents[k]
+			} else {
+				local[k] = -c.HalfExtents[k]
+			}
+		}
+		TransformPointToWorldFrame(&pos, &rot, &local, &corners[i])
+	}
+	drawBoxEdges(d, &corners, DebugGray)
+	DrawTransform(d, &c.Transform, 0.5)
+}
+
+/**
+ * Axis aligned bounds of the particles, not including Radius.
+ * @method aabb
+ * @param {Vec3} min
+ * @param {Vec3} max
+ */
+func (sb *SoftBody) AABB(min, max *Vec3) {
+	if len(sb.Particles) == 0 {
+		min.Set(0, 0, 0)
+		max.Set(0, 0, 0)
+		return
+	}
+	min.Copy(&sb.Particles[0].Position)
+	max.Copy(&sb.Particles[0].Position)
+	for i := range sb.Particles {
+		p := &sb.Particles[i].Position
+		for k := 0; k < 3; k++ {
+			if p[k] < min[k] {
+				min[k] = p[k]
+			}
+			if p[k] > max[k] {
+				max[k] = p[k]
+			}
+		}
+	}
+}
+
+/**
+ * Draw particles (pinned ones red), distance constraints, colliders and the bounding box.
+ * @method debugDraw
+ * @param {DebugDrawer} d
+ */
+func (sb *SoftBody) DebugDraw(d DebugDrawer) {
+	for i := range sb.Distances {
+		c := &sb.Distances[i]
+		d.DrawLine(&sb.Particles[c.A].Position, &sb.Particles[c.B].Position, DebugWhite)
+	}
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		if p.invMass() == 0 {
+			d.DrawPoint(&p.Position, DebugRed)
+		} else {
+			d.DrawPoint(&p.Position, DebugGreen)
+		}
+	}
+	for _, c := range sb.Colliders {
+		if dd, ok := c.(DebugDrawable); ok {
+			dd.DebugDraw(d)
+		}
+	}
+
+	var min, max Vec3
+	sb.AABB(&min, &max)
+	DrawAABB(d, &min, &max, DebugBlue)
+}
+
+
+/**
+ * Writes debug geometry as a Wavefront OBJ file with line and point elements. Colors are dropped.
+ * @class OBJDebugDrawer
+ */
+type OBJDebugDrawer struct {
+	w *bufio.Writer
+	n int
+	err error
+}
+
+func NewOBJDebugDrawer(w io.Writer) (*OBJDebugDrawer) {
+	return &OBJDebugDrawer{ w: bufio.NewWriter(w) }
+}
+
+func (d *OBJDebugDrawer) vertex(p *Vec3) (int) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, "v %v %v %v\n", p[0], p[1], p[2])
+	}
+	d.n++
+	return d.n
+}
+
+func (d *OBJDebugDrawer) DrawLine(from, to *Vec3, color DebugColor) {
+	a, b := d.vertex(from), d.vertex(to)
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, "l %d %d\n", a, b)
+	}
+}
+
+func (d *OBJDebugDrawer) DrawPoint(p *Vec3, color DebugColor) {
+	a := d.vertex(p)
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, "p %d\n", a)
+	}
+}
+
+/**
+ * Write buffered output and report the first write error.
+ * @method flush
+ */
+func (d *OBJDebugDrawer) Flush() (error) {
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.Flush()
+}
+
+
+type svgLine struct {
+	a, b [2]Number
+	color DebugColor
+}
+
+/**
+ * Collects debug geometry projected onto two world axes and writes it as an SVG image.
+ * @class SVGDebugDrawer
+ * @param {int} U World axis drawn to the right, 0 for x
+ * @param {int} V World axis drawn upwards, 1 for y
+ * @param {Number} Scale Pixels per unit
+ */
+type SVGDebugDrawer struct {
+	U, V int
+	Scale Number
+
+	lines []svgLine
+	points []svgLine
+}
+
+func NewSVGDebugDrawer(u, v int) (*SVGDebugDrawer) {
+	return &SVGDebugDrawer{ U: u, V: v, Scale: 100 }
+}
+
+func (d *SVGDebugDrawer) project(p *Vec3) ([2]Number) {
+	return [2]Number{ p[d.U], p[d.V] }
+}
+
+func (d *SVGDebugDrawer) DrawLine(from, to *Vec3, color DebugColor) {
+	d.lines = append(d.lines, svgLine{ d.project(from), d.project(to), color })
+}
+
+func (d *SVGDebugDrawer) DrawPoint(p *Vec3, color DebugColor) {
+	pp := d.project(p)
+	d.points = append(d.points, svgLine{ pp, pp, color })
+}
+
+/**
+ * Clear the collected geometry, e.g. to draw the next step.
+ * @method reset
+ */
+func (d *SVGDebugDrawer) Reset() {
+	d.lines = d.lines[:0]
+	d.points = d.points[:0]
+}
+
+/**
+ * Write the collected geometry, the view box fits all of it.
+ * @method writeTo
+ * @param {io.Writer} w
+ */
+func (d *SVGDebugDrawer) WriteTo(w io.Writer) (int64, error) {
+	min := [2]Number{ 0, 0 }
+	max := [2]Number{ 0, 0 }
+	first := true
+	grow := func(p [2]Number) {
+		for k := 0; k < 2; k++ {
+			if first || p[k] < min[k] {
+				min[k] = p[k]
+			}
+			if first || p[k] > max[k] {
+				max[k] = p[k]
+			}
+		}
+		first = false
+	}
+	for _, l := range d.lines {
+		grow(l.a)
+		grow(l.b)
+	}
+	for _, p := range d.points {
+		grow(p.a)
+	}
+
+	s := d.Scale
+	if s <= 0 {
+		s = 1
+	}
+	margin := 10 / s
+	x0, y0 := min[0] - margin, min[1] - margin
+	width, height := (max[0] - min[0] + 2 * margin) * s, (max[1] - min[1] + 2 * margin) * s
+	// world V points up, svg y points down
+	pt := func(p [2]Number) (string, string) {
+		x := (p[0] - x0) * s
+		y := height - (p[1] - y0) * s
+		return strconv.FormatFloat(float64(x), 'f', 2, 64), strconv.FormatFloat(float64(y), 'f', 2, 64)
+	}
+
+	cw := &countWriter{ w: bufio.NewWriter(w) }
+	fmt.Fprintf(cw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.2f %.2f\">\n", width, height, width, height)
+	fmt.Fprintf(cw, "<rect width=\"100%%\" height=\"100%%\" fill=\"#202020\"/>\n")
+	for _, l := range d.lines {
+		x1, y1 := pt(l.a)
+		x2, y2 := pt(l.b)
+		fmt.Fprintf(cw, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"#%06x\" stroke-width=\"1\"/>\n", x1, y1, x2, y2, uint32(l.color))
+	}
+	for _, p := range d.points {
+		x, y := pt(p.a)
+		fmt.Fprintf(cw, "<circle cx=\"%s\" cy=\"%s\" r=\"2\" fill=\"#%06x\"/>\n", x, y, uint32(p.color))
+	}
+	fmt.Fprintf(cw, "</svg>\n")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+type countWriter struct {
+	w *bufio.Writer
+	n int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
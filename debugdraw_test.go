@@ -0,0 +1,73 @@
+package physics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type countDrawer struct {
+	lines, points int
+	ends []Vec3
+}
+
+func (d *countDrawer) DrawLine(from, to *Vec3, color DebugColor) {
+	d.lines++
+	d.ends = append(d.ends, *to)
+}
+
+func (d *countDrawer) DrawPoint(p *Vec3, color DebugColor) {
+	d.points++
+}
+
+func TestDrawTransform(t *testing.T) {
+
+	tf := Transform{ Pos: NewVec3().Set(1, 2, 3), Rot: NewQuat() }
+	tf.Rot.SetFromAxisAngle(NewVec3().Set(0, 0, 1), 3.141592653589793 / 2)
+
+	d := &countDrawer{}
+	DrawTransform(d, &tf, 2)
+	if d.lines != 3 {
+		t.Fatal("Error drawing transform axes, got ", d.lines)
+	}
+	// x axis rotated onto y
+	if !d.ends[0].AlmostEquals(NewVec3().Set(1, 4, 3)) {
+		t.Error("Error drawing rotated x axis, got ", d.ends[0])
+	}
+
+}
+
+func TestSoftBodyDebugDraw(t *testing.T) {
+
+	rope := NewRope(NewVec3().Set(0, 5, 0), NewVec3().Set(2, 5, 0), 4, 1, 1, true, false)
+	rope.Colliders = append(rope.Colliders, &BoxCollider{ HalfExtents: Vec3{ 1, 1, 1 } })
+
+	d := &countDrawer{}
+	rope.DebugDraw(d)
+	// distance constraints + box edges + box axes + aabb edges
+	if d.points != 5 || d.lines != len(rope.Distances) + 12 + 3 + 12 {
+		t.Error("Error drawing soft body, got ", d.points, d.lines)
+	}
+
+	var buf bytes.Buffer
+	obj := NewOBJDebugDrawer(&buf)
+	rope.DebugDraw(obj)
+	if err := obj.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\nl "); n != d.lines {
+		t.Error("Error writing OBJ lines, got ", n)
+	}
+
+	buf.Reset()
+	svg := NewSVGDebugDrawer(0, 1)
+	rope.DebugDraw(svg)
+	if _, err := svg.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); !strings.HasPrefix(s, "<svg") || strings.Count(s, "<line") != d.lines || strings.Count(s, "<circle") != 5 {
+		t.Error("Error writing SVG, got ", s)
+	}
+
+}
+
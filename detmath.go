@@ -0,0 +1,182 @@
+package physics
+
+import (
+	"math"
+)
+
+// Deterministic replacements for the math functions used while stepping and by Quat.
+// math.Exp, math.Log and math.Pow use assembly on amd64 and pure Go on arm64, and the pure Go
+// versions (also of the trigonometric functions) may be fused into FMA instructions, so their results
+// differ between architectures. These only use +, -, *, /, Sqrt and exact operations (Frexp, Ldexp, Floor),
+// every product is rounded on its own, so they give the same bits everywhere. They are accurate to a few ulp.
+
+// log(x) for finite x > 0
+func detLog(x float64) (float64) {
+	if x <= 0 || math.IsInf(x, 0) || math.IsNaN(x) {
+		return math.Log(x) // -Inf, NaN or +Inf, exact on every architecture
+	}
+
+	// x = m * 2^e, m in [sqrt(1/2), sqrt(2))
+	m, e := math.Frexp(x)
+	if m < math.Sqrt2 / 2 {
+		m *= 2
+		e--
+	}
+
+	// log(m) = 2 * atanh(s) = 2 * (s + s^3/3 + s^5/5 + ...), |s| <= 0.172
+	s := (m - 1) / (m + 1)
+	s2 := float64(s * s)
+	var sum float64
+	term := s
+	for k := 1; k <= 27; k += 2 {
+		sum += term / float64(k)
+		term = float64(term * s2)
+	}
+	return float64(float64(e) * math.Ln2) + float64(2 * sum)
+}
+
+// e^x
+func detExp(x float64) (float64) {
+	switch {
+	case math.IsNaN(x):
+		return x
+	case x > 709.8:
+		return math.Inf(1)
+	case x < -745.2:
+		return 0
+	}
+
+	// x = k*ln2 + r, |r| <= ln2/2
+	const ln2Hi = 6.93147180369123816490e-01 // same split as math.Exp
+	const ln2Lo = 1.90821492927058770002e-10
+	k := math.Floor(x / math.Ln2 + 0.5)
+	r := x - float64(k * ln2Hi) - float64(k * ln2Lo)
+
+	// Taylor series, r^21/21! < 1e-29
+	sum, term := 1.0, 1.0
+	for i := 1; i <= 20; i++ {
+		term = float64(term * r) / float64(i)
+		sum += term
+	}
+	return math.Ldexp(sum, int(k))
+}
+
+// x^y for x >= 0
+func detPow(x, y float64) (float64) {
+	switch {
+	case y == 0 || x == 1:
+		return 1
+	case x == 0:
+		if y > 0 {
+			return 0
+		}
+		return math.Inf(1)
+	case x < 0:
+		return math.NaN()
+	}
+	return detExp(float64(y * detLog(x)))
+}
+
+// x = k*2pi + r, |r| <= pi
+func detReduce(x float64) (float64) {
+	const twoPiHi = 6.28318530717958623200e+00
+	const twoPiLo = 2.44929359829470635445e-16
+	k := math.Floor(x / (2 * math.Pi) + 0.5)
+	return x - float64(k * twoPiHi) - float64(k * twoPiLo)
+}
+
+// sin(x), accurate for |x| up to about 1e6
+func detSin(x float64) (float64) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.NaN()
+	}
+	r := detReduce(x)
+
+	// Taylor series, pi^29/29! < 1e-16
+	r2 := float64(r * r)
+	sum, term := r, r
+	for i := 2; i <= 28; i += 2 {
+		term = -float64(term * r2) / float64(i * (i + 1))
+		sum += term
+	}
+	return sum
+}
+
+// cos(x), accurate for |x| up to about 1e6
+func detCos(x float64) (float64) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return math.NaN()
+	}
+	r := detReduce(x)
+
+	// Taylor series, pi^30/30! < 1e-17
+	r2 := float64(r * r)
+	sum, term := 1.0, 1.0
+	for i := 1; i <= 29; i += 2 {
+		term = -float64(term * r2) / float64(i * (i + 1))
+		sum += term
+	}
+	return sum
+}
+
+// sin(x), cos(x), like math.Sincos
+func detSincos(x float64) (float64, float64) {
+	return detSin(x), detCos(x)
+}
+
+// atan(x)
+func detAtan(x float64) (float64) {
+	switch {
+	case math.IsNaN(x) || x == 0:
+		return x
+	case math.IsInf(x, 0):
+		return math.Copysign(math.Pi / 2, x)
+	}
+
+	// atan(-x) = -atan(x), then bring x to |x| <= tan(pi/8)
+	sign := 1.0
+	if x < 0 {
+		sign, x = -1, -x
+	}
+	base := 0.0
+	switch {
+	case x > math.Sqrt2 + 1: // tan(3pi/8), atan(x) = pi/2 - atan(1/x)
+		base, x = math.Pi / 2, -1 / x
+	case x > math.Sqrt2 - 1: // tan(pi/8), atan(x) = pi/4 + atan((x-1)/(x+1))
+		base, x = math.Pi / 4, (x - 1) / (x + 1)
+	}
+
+	// Taylor series, tan(pi/8)^47/47 < 1e-19
+	x2 := float64(x * x)
+	sum, term := 0.0, x
+	for k := 1; k <= 45; k += 2 {
+		sum += term / float64(k)
+		term = -float64(term * x2)
+	}
+	return float64(sign * (base + sum))
+}
+
+// atan2(y, x), like math.Atan2
+func detAtan2(y, x float64) (float64) {
+	if x == 0 || y == 0 || math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return math.Atan2(y, x) // zeros, Inf and NaN give exact constants on every architecture
+	}
+	r := detAtan(y / x)
+	switch {
+	case x > 0:
+		return r
+	case y > 0:
+		return r + math.Pi
+	}
+	return r - math.Pi
+}
+
+// asin(x), NaN outside [-1, 1]
+func detAsin(x float64) (float64) {
+	return detAtan2(x, math.Sqrt(float64((1 - x) * (1 + x))))
+}
+
+// acos(x), NaN outside [-1, 1]
+func detAcos(x float64) (float64) {
+	return detAtan2(math.Sqrt(float64((1 - x) * (1 + x))), x)
+}
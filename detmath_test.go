@@ -0,0 +1,65 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDetMath(t *testing.T) {
+
+	r := rand.New(rand.NewSource(3))
+	rel := func(got, want float64) (float64) {
+		if want == 0 {
+			return math.Abs(got)
+		}
+		return math.Abs(got - want) / math.Abs(want)
+	}
+	for i := 0; i < 10000; i++ {
+		x := r.Float64()
+		y := r.Float64() * 4
+		if e := rel(detPow(x, y), math.Pow(x, y)); e > 1e-14 {
+			t.Error("Error detPow, got ", x, y, detPow(x, y), math.Pow(x, y))
+		}
+		if e := rel(detLog(x * 1e3), math.Log(x * 1e3)); e > 1e-14 {
+			t.Error("Error detLog, got ", x, detLog(x * 1e3), math.Log(x * 1e3))
+		}
+		z := (x - 0.5) * 1400
+		if e := rel(detExp(z), math.Exp(z)); e > 1e-13 {
+			t.Error("Error detExp, got ", z, detExp(z), math.Exp(z))
+		}
+		w := (x - 0.5) * 2000
+		if e := math.Abs(detSin(w) - math.Sin(w)); e > 1e-13 {
+			t.Error("Error detSin, got ", w, detSin(w), math.Sin(w))
+		}
+		if e := math.Abs(detCos(w) - math.Cos(w)); e > 1e-13 {
+			t.Error("Error detCos, got ", w, detCos(w), math.Cos(w))
+		}
+		// math.Asin and math.Acos use 1 - x*x and lose digits near +-1, the det versions are closer to libm there
+		c := x * 2 - 1
+		if e := math.Abs(detAsin(c) - math.Asin(c)); e > 1e-13 {
+			t.Error("Error detAsin, got ", c, detAsin(c), math.Asin(c))
+		}
+		if e := math.Abs(detAcos(c) - math.Acos(c)); e > 1e-13 {
+			t.Error("Error detAcos, got ", c, detAcos(c), math.Acos(c))
+		}
+		a, b := r.NormFloat64() * math.Pow(10, r.Float64() * 8 - 4), r.NormFloat64()
+		if e := rel(detAtan2(a, b), math.Atan2(a, b)); e > 1e-15 {
+			t.Error("Error detAtan2, got ", a, b, detAtan2(a, b), math.Atan2(a, b))
+		}
+	}
+
+	for _, c := range [][2]float64{ { 0, 1 }, { 0, -1 }, { math.Copysign(0, -1), -1 }, { 1, 0 }, { -1, 0 }, { math.Inf(1), 2 }, { 1, math.Inf(-1) } } {
+		if got, want := detAtan2(c[0], c[1]), math.Atan2(c[0], c[1]); got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Error("Error detAtan2 special case ", c, got, want)
+		}
+	}
+	if detAcos(1) != 0 || detAcos(-1) != math.Pi || detAsin(1) != math.Pi / 2 || !math.IsNaN(detAcos(1.5)) {
+		t.Error("Error detAcos/detAsin at the ends")
+	}
+
+	if detPow(0, 0.5) != 0 || detPow(1, 7) != 1 || detPow(0.3, 0) != 1 || !math.IsNaN(detPow(-1, 0.5)) {
+		t.Error("Error detPow special cases")
+	}
+
+}
@@ -0,0 +1,326 @@
+package physics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrEncodingLength = errors.New("physics: wrong number of components")
+	ErrEncodingNaN = errors.New("physics: can not encode NaN or Inf to JSON")
+)
+
+func bitSizeOf[T Float]() (int) {
+	var n T
+	if _, ok := any(n).(float32); ok {
+		return 32
+	}
+	return 64
+}
+
+// Binary forms store every component as a little endian float64, like the SoftBody snapshots.
+func appendNumbers[T Float](b []byte, ns ...T) ([]byte) {
+	for _, n := range ns {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(float64(n)))
+	}
+	return b
+}
+
+func readNumbers[T Float](data []byte, ns []T) (error) {
+	if len(data) != 8 * len(ns) {
+		return ErrEncodingLength
+	}
+	for i := range ns {
+		ns[i] = T(math.Float64frombits(binary.LittleEndian.Uint64(data[8 * i:])))
+	}
+	return nil
+}
+
+// Text forms are the components separated by spaces, commas are accepted as well when parsing.
+func appendText[T Float](b []byte, ns ...T) ([]byte) {
+	for i, n := range ns {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendFloat(b, float64(n), 'g', -1, bitSizeOf[T]())
+	}
+	return b
+}
+
+func parseText[T Float](text []byte, ns []T) (error) {
+	fields := strings.FieldsFunc(string(text), func(r rune) (bool) {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(fields) != len(ns) {
+		return ErrEncodingLength
+	}
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, bitSizeOf[T]())
+		if err != nil {
+			return err
+		}
+		ns[i] = T(n)
+	}
+	return nil
+}
+
+// JSON forms are objects with one key per component, arrays are accepted as well when decoding.
+func appendJSON[T Float](b []byte, keys string, ns ...T) ([]byte, error) {
+	b = append(b, '{')
+	for i, n := range ns {
+		f := float64(n)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrEncodingNaN
+		}
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '"', keys[i], '"', ':')
+		b = strconv.AppendFloat(b, f, 'g', -1, bitSizeOf[T]())
+	}
+	b = append(b, '}')
+	return b, nil
+}
+
+func isJSONNull(data []byte) (bool) {
+	return string(bytes.TrimSpace(data)) == "null"
+}
+
+// Keys missing from an object keep their current value, like encoding/json does for struct fields.
+func parseJSON[T Float](data []byte, keys string, ns []T) (error) {
+	return parseJSONAs[T, float64](data, keys, ns)
+}
+
+// parseJSON decoding each number as a J, so J can accept more than JSON numbers
+func parseJSONAs[T Float, J ~float64](data []byte, keys string, ns []T) (error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return ErrEncodingLength
+	}
+	if isJSONNull(data) {
+		return nil
+	}
+
+	switch data[0] {
+	case '[':
+		var arr []J
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return err
+		}
+		if len(arr) != len(ns) {
+			return ErrEncodingLength
+		}
+		for i, n := range arr {
+			ns[i] = T(n)
+		}
+		return nil
+	default:
+		var obj map[string]J
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		for i := range ns {
+			if n, ok := obj[keys[i:i + 1]]; ok {
+				ns[i] = T(n)
+			}
+		}
+		return nil
+	}
+}
+
+
+func (v Vec3T[T]) MarshalBinary() ([]byte, error) {
+	return appendNumbers(nil, v[0], v[1], v[2]), nil
+}
+
+func (v *Vec3T[T]) UnmarshalBinary(data []byte) (error) {
+	return readNumbers(data, v[:])
+}
+
+func (v Vec3T[T]) MarshalText() ([]byte, error) {
+	return appendText(nil, v[0], v[1], v[2]), nil
+}
+
+func (v *Vec3T[T]) UnmarshalText(text []byte) (error) {
+	return parseText(text, v[:])
+}
+
+/**
+ * Encode as {"x":1,"y":2,"z":3}
+ * @method marshalJSON
+ */
+func (v Vec3T[T]) MarshalJSON() ([]byte, error) {
+	return appendJSON(nil, "xyz", v[0], v[1], v[2])
+}
+
+/**
+ * Decode {"x":1,"y":2,"z":3} or [1,2,3]
+ * @method unmarshalJSON
+ */
+func (v *Vec3T[T]) UnmarshalJSON(data []byte) (error) {
+	return parseJSON(data, "xyz", v[:])
+}
+
+
+func (q QuatT[T]) MarshalBinary() ([]byte, error) {
+	return appendNumbers(nil, q[0], q[1], q[2], q[3]), nil
+}
+
+func (q *QuatT[T]) UnmarshalBinary(data []byte) (error) {
+	return readNumbers(data, q[:])
+}
+
+func (q QuatT[T]) MarshalText() ([]byte, error) {
+	return appendText(nil, q[0], q[1], q[2], q[3]), nil
+}
+
+func (q *QuatT[T]) UnmarshalText(text []byte) (error) {
+	return parseText(text, q[:])
+}
+
+/**
+ * Encode as {"x":0,"y":0,"z":0,"w":1}
+ * @method marshalJSON
+ */
+func (q QuatT[T]) MarshalJSON() ([]byte, error) {
+	return appendJSON(nil, "xyzw", q[0], q[1], q[2], q[3])
+}
+
+/**
+ * Decode {"x":0,"y":0,"z":0,"w":1} or [0,0,0,1], order x, y, z, w
+ * @method unmarshalJSON
+ */
+func (q *QuatT[T]) UnmarshalJSON(data []byte) (error) {
+	return parseJSON(data, "xyzw", q[:])
+}
+
+
+// components of a Transform, nil Pos and Rot count as zero and identity
+func (tf *TransformT[T]) values() (Vec3T[T], QuatT[T]) {
+	pos := Vec3T[T]{}
+	rot := QuatT[T]{ 0, 0, 0, 1 }
+	if tf.Pos != nil {
+		pos = *tf.Pos
+	}
+	if tf.Rot != nil {
+		rot = *tf.Rot
+	}
+	return pos, rot
+}
+
+func (tf *TransformT[T]) setValues(pos Vec3T[T], rot QuatT[T]) {
+	if tf.Pos == nil {
+		tf.Pos = &Vec3T[T]{}
+	}
+	if tf.Rot == nil {
+		tf.Rot = &QuatT[T]{}
+	}
+	*tf.Pos = pos
+	*tf.Rot = rot
+}
+
+/**
+ * Position followed by rotation, 7 float64.
+ * @method marshalBinary
+ */
+func (tf TransformT[T]) MarshalBinary() ([]byte, error) {
+	pos, rot := tf.values()
+	return appendNumbers(nil, pos[0], pos[1], pos[2], rot[0], rot[1], rot[2], rot[3]), nil
+}
+
+func (tf *TransformT[T]) UnmarshalBinary(data []byte) (error) {
+	var ns [7]T
+	if err := readNumbers(data, ns[:]); err != nil {
+		return err
+	}
+	tf.setValues(Vec3T[T]{ ns[0], ns[1], ns[2] }, QuatT[T]{ ns[3], ns[4], ns[5], ns[6] })
+	return nil
+}
+
+/**
+ * "x y z qx qy qz qw"
+ * @method marshalText
+ */
+func (tf TransformT[T]) MarshalText() ([]byte, error) {
+	pos, rot := tf.values()
+	return appendText(nil, pos[0], pos[1], pos[2], rot[0], rot[1], rot[2], rot[3]), nil
+}
+
+func (tf *TransformT[T]) UnmarshalText(text []byte) (error) {
+	var ns [7]T
+	if err := parseText(text, ns[:]); err != nil {
+		return err
+	}
+	tf.setValues(Vec3T[T]{ ns[0], ns[1], ns[2] }, QuatT[T]{ ns[3], ns[4], ns[5], ns[6] })
+	return nil
+}
+
+type transformJSON[T Float] struct {
+	Position *Vec3T[T] `json:"position,omitempty"`
+	Quaternion *QuatT[T] `json:"quaternion,omitempty"`
+}
+
+/**
+ * Encode as {"position":{"x":0,"y":0,"z":0},"quaternion":{"x":0,"y":0,"z":0,"w":1}}
+ * @method marshalJSON
+ */
+func (tf TransformT[T]) MarshalJSON() ([]byte, error) {
+	pos, rot := tf.values()
+	return json.Marshal(transformJSON[T]{ &pos, &rot })
+}
+
+/**
+ * Decode the MarshalJSON form, a missing position is kept or zero, a missing quaternion is kept or identity.
+ * @method unmarshalJSON
+ */
+func (tf *TransformT[T]) UnmarshalJSON(data []byte) (error) {
+	if isJSONNull(data) {
+		return nil
+	}
+	pos, rot := tf.values()
+	tj := transformJSON[T]{ &pos, &rot }
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return err
+	}
+	tf.setValues(pos, rot)
+	return nil
+}
+
+
+var axisOrderNames = [...]string{ "XYZ", "YXZ", "ZXY", "ZYX", "YZX", "XZY" }
+
+func (o AxisOrder) String() (string) {
+	if o < 0 || int(o) >= len(axisOrderNames) {
+		return "AxisOrder(" + strconv.Itoa(int(o)) + ")"
+	}
+	return axisOrderNames[o]
+}
+
+/**
+ * Encode as the three-character name, e.g. "YZX"
+ * @method marshalText
+ */
+func (o AxisOrder) MarshalText() ([]byte, error) {
+	if o < 0 || int(o) >= len(axisOrderNames) {
+		return nil, fmt.Errorf("physics: invalid AxisOrder %d", int(o))
+	}
+	return []byte(axisOrderNames[o]), nil
+}
+
+func (o *AxisOrder) UnmarshalText(text []byte) (error) {
+	name := strings.ToUpper(string(text))
+	for i, n := range axisOrderNames {
+		if n == name {
+			*o = AxisOrder(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("physics: unknown AxisOrder %q", string(text))
+}
+
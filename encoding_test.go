@@ -0,0 +1,115 @@
+package physics
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler = Vec3{}
+	_ encoding.BinaryUnmarshaler = &Quat{}
+	_ encoding.TextMarshaler = Transform{}
+	_ json.Unmarshaler = &Transform{}
+)
+
+func TestVec3JSON(t *testing.T) {
+
+	v := NewVec3().Set(1, -2.5, 3)
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `{"x":1,"y":-2.5,"z":3}` {
+		t.Error("Error encoding Vec3, got ", string(data), err)
+	}
+
+	var u Vec3
+	if err := json.Unmarshal(data, &u); err != nil || !u.IsEquals(v) {
+		t.Error("Error decoding Vec3 object, got ", u, err)
+	}
+
+	u.Set(7, 7, 7)
+	if err := json.Unmarshal([]byte(`{"y":2}`), &u); err != nil || !u.IsEquals(NewVec3().Set(7, 2, 7)) {
+		t.Error("missing keys should be kept, got ", u, err)
+	}
+
+	if err := json.Unmarshal([]byte(`[4, 5, 6]`), &u); err != nil || !u.IsEquals(NewVec3().Set(4, 5, 6)) {
+		t.Error("Error decoding Vec3 array, got ", u, err)
+	}
+	if err := json.Unmarshal([]byte(`[4, 5]`), &u); err == nil {
+		t.Error("short array should fail, got ", u)
+	}
+
+	v[0] = Number(math.NaN())
+	if _, err := json.Marshal(v); err == nil {
+		t.Error("NaN should not encode")
+	}
+
+}
+
+func TestQuatJSON(t *testing.T) {
+
+	var s struct {
+		Rot Quat `json:"rot"`
+	}
+	s.Rot.Set(0, 0, 0, 1)
+
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != `{"rot":{"x":0,"y":0,"z":0,"w":1}}` {
+		t.Error("Error encoding Quat by value, got ", string(data), err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"rot":[0.5,0.5,0.5,0.5]}`), &s); err != nil || !s.Rot.IsEquals(NewQuat().Set(0.5, 0.5, 0.5, 0.5)) {
+		t.Error("Error decoding Quat array, got ", s.Rot, err)
+	}
+
+}
+
+func TestTransformJSON(t *testing.T) {
+
+	var tf Transform
+	data, err := json.Marshal(tf)
+	if err != nil || string(data) != `{"position":{"x":0,"y":0,"z":0},"quaternion":{"x":0,"y":0,"z":0,"w":1}}` {
+		t.Error("nil Transform should encode as identity, got ", string(data), err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"position":[1,2,3]}`), &tf); err != nil {
+		t.Fatal(err)
+	}
+	if tf.Pos == nil || tf.Rot == nil || !tf.Pos.IsEquals(NewVec3().Set(1, 2, 3)) || !tf.Rot.IsEquals(NewQuat()) {
+		t.Error("Error decoding Transform, got ", tf.Pos, tf.Rot)
+	}
+
+}
+
+func TestEncodingBinaryText(t *testing.T) {
+
+	tf := Transform{ Pos: NewVec3().Set(1, 2, 3), Rot: NewQuat().SetFromAxisAngle(NewVec3().Set(0, 1, 0), 1) }
+
+	data, _ := tf.MarshalBinary()
+	if len(data) != 7 * 8 {
+		t.Error("Error encoding Transform binary, got ", len(data))
+	}
+	var tb Transform
+	if err := tb.UnmarshalBinary(data); err != nil || !tb.Pos.IsEquals(tf.Pos) || !tb.Rot.IsEquals(tf.Rot) {
+		t.Error("Error decoding Transform binary, got ", tb.Pos, tb.Rot, err)
+	}
+	if err := tb.Pos.UnmarshalBinary(data[:8]); err != ErrEncodingLength {
+		t.Error("short Vec3 binary should fail, got ", err)
+	}
+
+	text, _ := tf.MarshalText()
+	var tt Transform
+	if err := tt.UnmarshalText(text); err != nil || !tt.Pos.IsEquals(tf.Pos) || !tt.Rot.IsEquals(tf.Rot) {
+		t.Error("Error decoding Transform text, got ", string(text), tt.Pos, tt.Rot, err)
+	}
+
+	var v Vec3T[float32]
+	if err := v.UnmarshalText([]byte("1, 2.5, -3")); err != nil || v != (Vec3T[float32]{ 1, 2.5, -3 }) {
+		t.Error("Error decoding Vec3 text, got ", v, err)
+	}
+	if text, _ := v.MarshalText(); string(text) != "1 2.5 -3" {
+		t.Error("Error encoding Vec3 text, got ", string(text))
+	}
+
+}
+
@@ -0,0 +1,188 @@
+package physics
+
+type EventType int
+
+const (
+	EventPreStep EventType = iota // 0
+	EventPostStep // 1
+	EventBeginContact // 2
+	EventEndContact // 3
+	EventCollide // 4
+
+	numEventTypes
+)
+
+/**
+ * Payload handed to listeners. The dispatcher reuses the same object, so listeners must copy what they want to keep.
+ * @class Event
+ * @param {EventType} Type
+ * @param {Object} Target The object that dispatched the event
+ * @param {Object} BodyA
+ * @param {Object} BodyB
+ * @param {Vec3} ContactPoint World space contact point, for contact and collide events
+ * @param {Vec3} ContactNormal World space contact normal, pointing from BodyA to BodyB
+ * @param {Number} ImpactVelocity Relative velocity along the contact normal
+ */
+type Event struct {
+	Type EventType
+	Target interface{}
+	BodyA interface{}
+	BodyB interface{}
+	ContactPoint Vec3
+	ContactNormal Vec3
+	ImpactVelocity Number
+}
+
+type EventListener func(e *Event)
+
+// ListenerID identifies a listener added with AddEventListener, Go funcs are not comparable.
+type ListenerID int
+
+type eventListener struct {
+	id ListenerID
+	fn EventListener
+}
+
+/**
+ * Base class for objects that dispatches events.
+ * Listeners may be added or removed while an event is being dispatched:
+ * new listeners are called from the next dispatch, removed ones are not called again.
+ * @class EventTarget
+ */
+type EventTarget struct {
+	listeners [numEventTypes][]eventListener
+	nextID ListenerID
+	dispatching int
+	dirty bool
+}
+
+/**
+ * Add an event listener
+ * @method addEventListener
+ * @param  {EventType} type
+ * @param  {Function} listener
+ * @return {ListenerID} Handle to remove the listener with
+ */
+func (et *EventTarget) AddEventListener(typ EventType, listener EventListener) (ListenerID) {
+	if typ < 0 || typ >= numEventTypes || listener == nil {
+		return 0
+	}
+	et.nextID++
+	et.listeners[typ] = append(et.listeners[typ], eventListener{ et.nextID, listener })
+	return et.nextID
+}
+
+/**
+ * Check if an event listener is added
+ * @method hasEventListener
+ * @param  {EventType} type
+ * @param  {ListenerID} id
+ * @return {Boolean}
+ */
+func (et *EventTarget) HasEventListener(typ EventType, id ListenerID) (bool) {
+	if typ < 0 || typ >= numEventTypes {
+		return false
+	}
+	for _, l := range et.listeners[typ] {
+		if l.id == id && l.fn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Check if any event listener of the given type is added
+ * @method hasAnyEventListener
+ * @param  {EventType} type
+ * @return {Boolean}
+ */
+func (et *EventTarget) HasAnyEventListener(typ EventType) (bool) {
+	if typ < 0 || typ >= numEventTypes {
+		return false
+	}
+	for _, l := range et.listeners[typ] {
+		if l.fn != nil {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Remove an event listener
+ * @method removeEventListener
+ * @param  {EventType} type
+ * @param  {ListenerID} id
+ * @return {Boolean} false if the listener was not found
+ */
+func (et *EventTarget) RemoveEventListener(typ EventType, id ListenerID) (bool) {
+	if typ < 0 || typ >= numEventTypes {
+		return false
+	}
+	list := et.listeners[typ]
+	for i, l := range list {
+		if l.id != id || l.fn == nil {
+			continue
+		}
+		if et.dispatching > 0 {
+			// keep the slice layout for the running dispatch, compact afterwards
+			list[i].fn = nil
+			et.dirty = true
+		} else {
+			copy(list[i:], list[i+1:])
+			list[len(list)-1] = eventListener{}
+			et.listeners[typ] = list[:len(list)-1]
+		}
+		return true
+	}
+	return false
+}
+
+/**
+ * Emit an event. e.Target is set to target.
+ * @method dispatchEvent
+ * @param  {Event} event
+ * @param  {Object} target
+ */
+func (et *EventTarget) DispatchEvent(e *Event, target interface{}) {
+	if e.Type < 0 || e.Type >= numEventTypes {
+		return
+	}
+	e.Target = target
+
+	et.dispatching++
+	defer et.endDispatch() // also when a listener panics
+	n := len(et.listeners[e.Type]) // listeners added during dispatch wait for the next one
+	for i := 0; i < n; i++ {
+		// index the field every time, an append from a listener may have moved the slice
+		if fn := et.listeners[e.Type][i].fn; fn != nil {
+			fn(e)
+		}
+	}
+}
+
+func (et *EventTarget) endDispatch() {
+	et.dispatching--
+	if et.dispatching == 0 && et.dirty {
+		et.compact()
+	}
+}
+
+func (et *EventTarget) compact() {
+	for typ, list := range et.listeners {
+		j := 0
+		for _, l := range list {
+			if l.fn != nil {
+				list[j] = l
+				j++
+			}
+		}
+		for k := j; k < len(list); k++ {
+			list[k] = eventListener{}
+		}
+		et.listeners[typ] = list[:j]
+	}
+	et.dirty = false
+}
+
@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"testing"
+)
+
+func TestEventTargetDispatch(t *testing.T) {
+
+	var et EventTarget
+	var got []EventType
+	var impact Number
+
+	et.AddEventListener(EventPreStep, func(e *Event) {
+		got = append(got, e.Type)
+	})
+	id := et.AddEventListener(EventCollide, func(e *Event) {
+		got = append(got, e.Type)
+		impact = e.ImpactVelocity
+	})
+
+	if !et.HasEventListener(EventCollide, id) {
+		t.Error("listener should be added:", id)
+	}
+	if et.HasAnyEventListener(EventEndContact) {
+		t.Error("no EndContact listener was added")
+	}
+
+	e := &Event{ Type: EventCollide, ImpactVelocity: 3 }
+	et.DispatchEvent(e, &et)
+	if len(got) != 1 || got[0] != EventCollide || impact != 3 {
+		t.Error("Error dispatching Collide, got ", got, impact)
+	}
+	if e.Target != &et {
+		t.Error("Target should be set by DispatchEvent, got ", e.Target)
+	}
+
+	if !et.RemoveEventListener(EventCollide, id) {
+		t.Error("RemoveEventListener should find:", id)
+	}
+	et.DispatchEvent(e, nil)
+	if len(got) != 1 {
+		t.Error("removed listener was called, got ", got)
+	}
+
+}
+
+func TestEventTargetRemoveDuringDispatch(t *testing.T) {
+
+	var et EventTarget
+	calls := 0
+
+	var first, second ListenerID
+	first = et.AddEventListener(EventPostStep, func(e *Event) {
+		calls++
+		et.RemoveEventListener(EventPostStep, first)
+		et.RemoveEventListener(EventPostStep, second)
+		et.AddEventListener(EventPostStep, func(e *Event) {
+			calls += 10
+		})
+	})
+	second = et.AddEventListener(EventPostStep, func(e *Event) {
+		calls += 100
+	})
+
+	e := &Event{ Type: EventPostStep }
+	et.DispatchEvent(e, nil)
+	if calls != 1 {
+		t.Error("Error dispatching while removing, got ", calls)
+	}
+
+	et.DispatchEvent(e, nil)
+	if calls != 11 {
+		t.Error("listener added during dispatch should run next time, got ", calls)
+	}
+	if et.HasEventListener(EventPostStep, first) || et.HasEventListener(EventPostStep, second) {
+		t.Error("removed listeners still present")
+	}
+
+}
+
+func TestEventTargetDispatchPanic(t *testing.T) {
+
+	var et EventTarget
+	var id ListenerID
+	id = et.AddEventListener(EventPreStep, func(e *Event) {
+		et.RemoveEventListener(EventPreStep, id)
+		panic("listener failed")
+	})
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		et.DispatchEvent(&Event{ Type: EventPreStep }, nil)
+	}()
+
+	if et.dispatching != 0 || et.dirty || len(et.listeners[EventPreStep]) != 0 {
+		t.Error("a panicking listener should still end the dispatch, got ", et.dispatching, et.dirty, len(et.listeners[EventPreStep]))
+	}
+
+}
+
+func TestEventTargetReuseEvent(t *testing.T) {
+
+	var a, b EventTarget
+	var got []interface{}
+	listener := func(e *Event) {
+		got = append(got, e.Target)
+	}
+	a.AddEventListener(EventPostStep, listener)
+	b.AddEventListener(EventPostStep, listener)
+
+	e := &Event{ Type: EventPostStep }
+	a.DispatchEvent(e, &a)
+	b.DispatchEvent(e, &b)
+	if len(got) != 2 || got[0] != &a || got[1] != &b {
+		t.Error("reused Event should report each target, got ", got)
+	}
+
+}
+
+func TestEventTargetDispatchAllocs(t *testing.T) {
+
+	var et EventTarget
+	et.AddEventListener(EventBeginContact, func(e *Event) {})
+	e := &Event{ Type: EventBeginContact }
+
+	allocs := testing.AllocsPerRun(100, func() {
+		et.DispatchEvent(e, nil)
+	})
+	if allocs != 0 {
+		t.Error("DispatchEvent should not allocate, got ", allocs)
+	}
+
+}
+
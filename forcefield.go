@@ -0,0 +1,213 @@
+package physics
+
+import (
+	"math"
+)
+
+/**
+ * A force evaluated for every simulated point mass each step.
+ * @class ForceField
+ */
+type ForceField interface {
+	// Force stores the force on a point mass in result and returns result.
+	// t is the simulated time, for fields that change over time.
+	Force(position, velocity *Vec3, mass Number, t Number, result *Vec3) (*Vec3)
+}
+
+/**
+ * Uniform wind with optional turbulence. The force is a linear drag towards the wind velocity.
+ * @class Wind
+ * @param {Vec3} Velocity
+ * @param {Number} Drag Drag coefficient, force = Drag * (wind - velocity)
+ * @param {Number} Turbulence Gust strength as a fraction of the wind speed
+ * @param {Number} Frequency Gusts per second
+ */
+type Wind struct {
+	Velocity Vec3
+	Drag Number
+	Turbulence Number
+	Frequency Number
+}
+
+func (f *Wind) Force(position, velocity *Vec3, mass Number, t Number, result *Vec3) (*Vec3) {
+	if result == nil {
+		result = &Vec3{}
+	}
+
+	result.Copy(&f.Velocity)
+	if f.Turbulence != 0 {
+		// cheap deterministic noise: phase shifted sines, the phase varies over space so gusts travel
+		amp := f.Turbulence * f.Velocity.Length()
+		w := float64(2 * math.Pi * float64(f.Frequency * t))
+		p0, p1, p2 := float64(position[0]), float64(position[1]), float64(position[2])
+		result[0] += Number(amp * Number(detSin(w + float64(0.7 * p1) + float64(1.3 * p2))))
+		result[1] += Number(amp * Number(detSin(float64(1.31 * w) + float64(1.1 * p0) + float64(0.5 * p2) + 2)))
+		result[2] += Number(amp * Number(detSin(float64(0.83 * w) + float64(0.9 * p0) + float64(1.7 * p1) + 4)))
+	}
+	result.VSub(velocity, result)
+	result.Scale(f.Drag, result)
+
+	return result
+}
+
+/**
+ * Point gravity well, force = Strength * mass / distance^2 towards Center.
+ * @class PointGravity
+ * @param {Vec3} Center
+ * @param {Number} Strength Negative values repel
+ * @param {Number} MinDistance Distance below which the force stops growing
+ * @param {Number} Radius No force beyond this distance, zero for unlimited
+ */
+type PointGravity struct {
+	Center Vec3
+	Strength Number
+	MinDistance Number
+	Radius Number
+}
+
+func (f *PointGravity) Force(position, velocity *Vec3, mass Number, t Number, result *Vec3) (*Vec3) {
+	if result == nil {
+		result = &Vec3{}
+	}
+
+	f.Center.VSub(position, result)
+	d := result.Normalize()
+	if d == 0 || (f.Radius > 0 && d > f.Radius) {
+		result.Set(0, 0, 0)
+		return result
+	}
+	if d < f.MinDistance {
+		d = f.MinDistance
+	}
+	result.Scale(f.Strength * mass / (d * d), result)
+
+	return result
+}
+
+/**
+ * Swirl around an axis. The tangential force fades out linearly to Radius, or stays constant when Radius is zero.
+ * @class Vortex
+ * @param {Vec3} Center A point on the axis
+ * @param {Vec3} Axis Unit length, the swirl is counter-clockwise around it
+ * @param {Number} Strength Tangential force at the axis
+ * @param {Number} Pull Force towards the axis, negative pushes out
+ * @param {Number} Lift Force along the axis
+ * @param {Number} Radius No force beyond this distance from the axis, zero for unlimited
+ */
+type Vortex struct {
+	Center Vec3
+	Axis Vec3
+	Strength Number
+	Pull Number
+	Lift Number
+	Radius Number
+}
+
+func (f *Vortex) Force(position, velocity *Vec3, mass Number, t Number, result *Vec3) (*Vec3) {
+	if result == nil {
+		result = &Vec3{}
+	}
+
+	// radial part of position - Center
+	var r Vec3
+	position.VSub(&f.Center, &r)
+	addScaled(&r, -r.Dot(&f.Axis), &f.Axis)
+	d := r.Normalize()
+	if d == 0 || (f.Radius > 0 && d >= f.Radius) {
+		result.Set(0, 0, 0)
+		return result
+	}
+
+	falloff := Number(1)
+	if f.Radius > 0 {
+		falloff = 1 - d / f.Radius
+	}
+	f.Axis.Cross(&r, result)
+	result.Scale(f.Strength * falloff, result)
+	addScaled(result, -f.Pull * falloff, &r)
+	addScaled(result, f.Lift * falloff, &f.Axis)
+
+	return result
+}
+
+/**
+ * Body of water below a horizontal surface, +y is up. Each point mass is a sphere of Radius,
+ * buoyancy lifts it by the weight of the water its submerged cap displaces and drag slows it by the submerged fraction.
+ * @class Water
+ * @param {Number} Height y of the surface
+ * @param {Number} Density Mass per unit volume, 1000 for water in kg/m^3
+ * @param {Number} Gravity Magnitude of gravity, like SoftBody.Gravity but positive
+ * @param {Number} Drag Linear drag coefficient when fully submerged, force = -Drag * velocity
+ * @param {Number} Radius Radius of the displacing sphere, usually SoftBody.Radius. Zero gives drag but no buoyancy
+ */
+type Water struct {
+	Height Number
+	Density Number
+	Gravity Number
+	Drag Number
+	Radius Number
+}
+
+func (f *Water) Force(position, velocity *Vec3, mass Number, t Number, result *Vec3) (*Vec3) {
+	if result == nil {
+		result = &Vec3{}
+	}
+
+	r := f.Radius
+	h := clamp(f.Height - position[1] + r, 0, 2 * r) // depth of the lowest point
+	if h == 0 && (r > 0 || position[1] >= f.Height) {
+		result.Set(0, 0, 0)
+		return result
+	}
+
+	// products are rounded so they are not fused into FMA, see Determinism in the README
+	fraction, volume := Number(1), Number(0)
+	if r > 0 {
+		// spherical cap, pi * h^2 * (3r - h) / 3
+		volume = Number(Number(math.Pi * Number(h * h)) * (Number(3 * r) - h)) / 3
+		fraction = volume / Number(Number(4 * math.Pi / 3 * Number(r * r)) * r)
+	}
+	k := -Number(f.Drag * fraction)
+	result[0] = Number(k * velocity[0])
+	result[1] = Number(k * velocity[1]) + Number(Number(f.Density * volume) * f.Gravity)
+	result[2] = Number(k * velocity[2])
+
+	return result
+}
+
+/**
+ * Radial impulse, fading out linearly to Radius.
+ * @class Explosion
+ * @param {Vec3} Center
+ * @param {Number} Strength Impulse at the center
+ * @param {Number} Radius
+ * @param {Function} Occluded Optional, reports if something blocks the blast between two points, e.g. a raycast
+ */
+type Explosion struct {
+	Center Vec3
+	Strength Number
+	Radius Number
+	Occluded func(from, to *Vec3) (bool)
+}
+
+/**
+ * Impulse on a point.
+ * @method impulse
+ * @param {Vec3} position
+ * @param {Vec3} result
+ * @return {bool} false if the point is out of range or occluded
+ */
+func (e *Explosion) Impulse(position *Vec3, result *Vec3) (bool) {
+	position.VSub(&e.Center, result)
+	d := result.Normalize()
+	if d >= e.Radius || (e.Occluded != nil && e.Occluded(&e.Center, position)) {
+		result.Set(0, 0, 0)
+		return false
+	}
+	if d == 0 {
+		result.Set(0, 1, 0) // Make something up
+	}
+	result.Scale(e.Strength * (1 - d / e.Radius), result)
+	return true
+}
+
@@ -0,0 +1,164 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestForceFieldPointGravity(t *testing.T) {
+
+	f := &PointGravity{ Center: Vec3{ 0, 0, 0 }, Strength: 8, MinDistance: 0.1 }
+	force := f.Force(NewVec3().Set(2, 0, 0), NewVec3(), 1, 0, nil)
+
+	if !force.AlmostEquals(NewVec3().Set(-2, 0, 0)) {
+		t.Error("Error Calculating PointGravity, got ", force)
+	}
+
+	f.Radius = 1
+	f.Force(NewVec3().Set(2, 0, 0), NewVec3(), 1, 0, force)
+	if !force.IsZero() {
+		t.Error("out of range PointGravity should be zero, got ", force)
+	}
+
+}
+
+func TestForceFieldVortex(t *testing.T) {
+
+	f := &Vortex{ Axis: Vec3{ 0, 1, 0 }, Strength: 1, Radius: 2 }
+	force := f.Force(NewVec3().Set(1, 5, 0), NewVec3(), 1, 0, nil)
+
+	// counter-clockwise around +y at +x points to -z, at half strength
+	if !force.AlmostEquals(NewVec3().Set(0, 0, -0.5)) {
+		t.Error("Error Calculating Vortex, got ", force)
+	}
+
+	// zero Radius is unlimited, like PointGravity
+	f.Radius = 0
+	f.Force(NewVec3().Set(100, 5, 0), NewVec3(), 1, 0, force)
+	if !force.AlmostEquals(NewVec3().Set(0, 0, -1)) {
+		t.Error("Error Calculating unlimited Vortex, got ", force)
+	}
+
+}
+
+func TestForceFieldWind(t *testing.T) {
+
+	f := &Wind{ Velocity: Vec3{ 4, 0, 0 }, Drag: 0.5 }
+	force := f.Force(NewVec3(), NewVec3().Set(2, 0, 0), 1, 0, nil)
+
+	if !force.AlmostEquals(NewVec3().Set(1, 0, 0)) {
+		t.Error("Error Calculating Wind, got ", force)
+	}
+
+	f.Turbulence = 0.5
+	f.Frequency = 1
+	a := f.Force(NewVec3(), NewVec3(), 1, 0.1, nil)
+	b := f.Force(NewVec3(), NewVec3(), 1, 0.1, nil)
+	c := f.Force(NewVec3(), NewVec3(), 1, 0.3, nil)
+	if !a.IsEquals(b) || a.IsEquals(c) {
+		t.Error("turbulence should vary over time only, got ", a, b, c)
+	}
+
+}
+
+func TestForceFieldWater(t *testing.T) {
+
+	f := &Water{ Height: 1, Density: 1, Gravity: 10, Drag: 4, Radius: 0.5 }
+	full := Number(4 * math.Pi / 3 * 0.125 * 10) // weight of the water a submerged sphere displaces
+	v := NewVec3().Set(0, -2, 0)
+
+	force := f.Force(NewVec3().Set(0, -3, 0), v, 1, 0, nil)
+	if !force.AlmostEquals(NewVec3().Set(0, full + 8, 0)) {
+		t.Error("Error Calculating submerged Water, got ", force)
+	}
+	f.Force(NewVec3().Set(0, 1, 0), v, 1, 0, force)
+	if !force.AlmostEquals(NewVec3().Set(0, full / 2 + 4, 0)) {
+		t.Error("Error Calculating half submerged Water, got ", force)
+	}
+	f.Force(NewVec3().Set(0, 1.5, 0), v, 1, 0, force)
+	if !force.IsZero() {
+		t.Error("Water above the surface should be zero, got ", force)
+	}
+
+	// a point has drag but no buoyancy
+	f.Radius = 0
+	f.Force(NewVec3().Set(0, 0.5, 0), v, 1, 0, force)
+	if !force.AlmostEquals(NewVec3().Set(0, 8, 0)) {
+		t.Error("Error Calculating Water drag on a point, got ", force)
+	}
+
+	// half the mass of the displaced water floats half submerged
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -10, 0)
+	sb.Radius = 0.5
+	sb.AddParticle(NewVec3().Set(0, 3, 0), full / 10 / 2)
+	sb.Fields = append(sb.Fields, &Water{ Height: 1, Density: 1, Gravity: 10, Drag: 2, Radius: sb.Radius })
+	for i := 0; i < 600; i++ {
+		sb.Step(1.0 / 60)
+	}
+	if y := sb.Particles[0].Position[1]; math.Abs(float64(y - 1)) > 1e-3 {
+		t.Error("particle should float at the surface, got ", y)
+	}
+
+}
+
+func TestForceFieldExplosion(t *testing.T) {
+
+	e := &Explosion{ Center: Vec3{ 0, 0, 0 }, Strength: 10, Radius: 4 }
+	var impulse Vec3
+
+	if !e.Impulse(NewVec3().Set(0, 0, 2), &impulse) || !impulse.AlmostEquals(NewVec3().Set(0, 0, 5)) {
+		t.Error("Error Calculating Explosion, got ", impulse)
+	}
+	if e.Impulse(NewVec3().Set(0, 0, 5), &impulse) {
+		t.Error("out of range Explosion should not apply, got ", impulse)
+	}
+
+	e.Occluded = func(from, to *Vec3) (bool) {
+		return to[0] < 0
+	}
+	if e.Impulse(NewVec3().Set(-1, 0, 0), &impulse) {
+		t.Error("occluded Explosion should not apply, got ", impulse)
+	}
+
+	sb := NewSoftBody()
+	a := sb.AddParticle(NewVec3().Set(1, 0, 0), 2)
+	b := sb.AddParticle(NewVec3().Set(-1, 0, 0), 2)
+	sb.ApplyExplosion(e)
+	if !sb.Particles[a].Velocity.AlmostEquals(NewVec3().Set(3.75, 0, 0)) || !sb.Particles[b].Velocity.IsZero() {
+		t.Error("Error applying Explosion, got ", sb.Particles[a].Velocity, sb.Particles[b].Velocity)
+	}
+
+}
+
+func TestSoftBodyFields(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Damping = 0
+	sb.Fields = append(sb.Fields, &PointGravity{ Center: Vec3{ 0, 0, 0 }, Strength: 1, MinDistance: 0.1 })
+	a := sb.AddParticle(NewVec3().Set(1, 0, 0), 1)
+
+	allocs := testing.AllocsPerRun(1, func() {
+		sb.Step(0.01)
+	})
+	if allocs != 0 {
+		t.Error("Step with fields should not allocate, got ", allocs)
+	}
+
+	if x := sb.Particles[a].Position[0]; x >= 1 || math.IsNaN(float64(x)) {
+		t.Error("particle should move towards the well, got ", sb.Particles[a].Position)
+	}
+
+	// drag sees the velocity from the start of the step, so a particle at rest gets the full gravity kick
+	sb = NewSoftBody()
+	sb.Damping = 0
+	sb.Gravity.Set(0, -10, 0)
+	sb.Fields = append(sb.Fields, &Wind{ Drag: 5 })
+	a = sb.AddParticle(NewVec3(), 1)
+	sb.Step(0.1)
+	if v := sb.Particles[a].Velocity; !v.AlmostEquals(NewVec3().Set(0, -1, 0)) {
+		t.Error("drag should not see this step's gravity, got ", v)
+	}
+
+}
+
@@ -0,0 +1,69 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func testTangents[T Float](t *testing.T) {
+
+	v := NewVec3T[T]().Set(1, 2, 3)
+	t1, t2 := NewVec3T[T](), NewVec3T[T]()
+	v.Tangents(t1, t2)
+
+	if !almostZero(v.Dot(t1)) || !almostZero(v.Dot(t2)) || !almostZero(t1.Dot(t2)) {
+		t.Errorf("%T: tangents should be orthogonal, got %v %v %v", v[0], v, t1, t2)
+	}
+
+}
+
+func testToEuler[T Float](t *testing.T) {
+
+	axis := NewVec3T[T]().Set(0, 0, 1)
+	q := NewQuatT[T]().SetFromAxisAngle(axis, math.Pi / 4)
+	euler := q.ToEuler(nil, YZX)
+
+	vok := NewVec3T[T]().Set(0, 0, math.Pi / 4)
+	if !vok.AlmostEquals(euler) {
+		t.Errorf("%T: Error Calculating ToEuler, got %v %v", q[0], euler, vok)
+	}
+
+}
+
+func testTransform[T Float](t *testing.T) {
+
+	tf := &TransformT[T]{
+		Pos: NewVec3T[T]().Set(1, 2, 3),
+		Rot: NewQuatT[T]().SetFromAxisAngle(NewVec3T[T]().Set(0, 1, 0), math.Pi / 2),
+	}
+
+	p := NewVec3T[T]().Set(1, 0, 0)
+	world := tf.PointToWorld(p, nil)
+	if !world.AlmostEquals(NewVec3T[T]().Set(1, 2, 2)) {
+		t.Errorf("%T: Error Calculating PointToWorld, got %v", p[0], world)
+	}
+
+	local := tf.PointToLocal(world, nil)
+	if !local.AlmostEquals(p) {
+		t.Errorf("%T: Error Calculating PointToLocal, got %v", p[0], local)
+	}
+
+	v := TransformVectorToLocalFrame(tf.Pos, tf.Rot, tf.VectorToWorldFrame(p, nil), nil)
+	if !v.AlmostEquals(p) {
+		t.Errorf("%T: Error Calculating VectorToLocalFrame, got %v", p[0], v)
+	}
+
+}
+
+func TestGenericFloat32(t *testing.T) {
+	testTangents[float32](t)
+	testToEuler[float32](t)
+	testTransform[float32](t)
+}
+
+func TestGenericFloat64(t *testing.T) {
+	testTangents[float64](t)
+	testToEuler[float64](t)
+	testTransform[float64](t)
+}
+
@@ -0,0 +1,502 @@
+package physics
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	glbMagic = "glTF"
+	glbChunkJSON = 0x4E4F534A
+	glbChunkBIN = 0x004E4942
+
+	gltfUnsignedByte = 5121
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt = 5125
+	gltfFloat = 5126
+
+	gltfTriangles = 4
+	gltfTriangleStrip = 5
+	gltfTriangleFan = 6
+)
+
+var (
+	ErrGLTFExternal = errors.New("physics: gltf buffer is not a local file or data uri")
+	ErrGLTFRange = errors.New("physics: gltf index or accessor out of range")
+)
+
+type gltfDoc struct {
+	Scene *int `json:"scene"`
+	Scenes []struct {
+		Nodes []int `json:"nodes"`
+	} `json:"scenes"`
+	Nodes []gltfNode `json:"nodes"`
+	Meshes []gltfMesh `json:"meshes"`
+	Accessors []gltfAccessor `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers []gltfBuffer `json:"buffers"`
+}
+
+type gltfNode struct {
+	Name string `json:"name"`
+	Mesh *int `json:"mesh"`
+	Children []int `json:"children"`
+	Matrix []float64 `json:"matrix"` // column major
+	Translation []float64 `json:"translation"`
+	Rotation []float64 `json:"rotation"` // x, y, z, w like Quat
+	Scale []float64 `json:"scale"`
+}
+
+type gltfMesh struct {
+	Name string `json:"name"`
+	Primitives []struct {
+		Attributes map[string]int `json:"attributes"`
+		Indices *int `json:"indices"`
+		Mode *int `json:"mode"`
+	} `json:"primitives"`
+}
+
+type gltfAccessor struct {
+	BufferView *int `json:"bufferView"`
+	ByteOffset int `json:"byteOffset"`
+	ComponentType int `json:"componentType"`
+	Count int `json:"count"`
+	Type string `json:"type"`
+	Sparse json.RawMessage `json:"sparse"`
+}
+
+type gltfBufferView struct {
+	Buffer int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type gltfBuffer struct {
+	URI string `json:"uri"`
+	ByteLength int `json:"byteLength"`
+}
+
+type gltfReader struct {
+	doc gltfDoc
+	buffers [][]byte
+}
+
+/**
+ * Read a glTF 2.0 file, either .gltf JSON or binary .glb.
+ * Every node with a mesh becomes one Mesh in scene space, with the node Transforms of its ancestors applied.
+ * Buffers are read from the glb chunk, data uris, or files inside dir. Network uris, absolute paths and paths leaving dir are refused.
+ * @method readGLTF
+ * @param {io.Reader} r
+ * @param {string} dir Directory of the file, "" to refuse external buffer files
+ * @return {[]Mesh}
+ */
+func ReadGLTF(r io.Reader, dir string) ([]*Mesh, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonChunk, binChunk := data, []byte(nil)
+	if len(data) >= 12 && string(data[:4]) == glbMagic {
+		jsonChunk, binChunk, err = splitGLB(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g := &gltfReader{}
+	if err := json.Unmarshal(jsonChunk, &g.doc); err != nil {
+		return nil, err
+	}
+	if err := g.loadBuffers(binChunk, dir); err != nil {
+		return nil, err
+	}
+
+	return g.meshes()
+}
+
+/**
+ * @method loadGLTF
+ * @param {string} path .gltf or .glb file
+ * @return {[]Mesh}
+ */
+func LoadGLTF(path string) ([]*Mesh, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGLTF(f, filepath.Dir(path))
+}
+
+func splitGLB(data []byte) ([]byte, []byte, error) {
+	version := binary.LittleEndian.Uint32(data[4:])
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if version != 2 {
+		return nil, nil, fmt.Errorf("physics: unsupported glb version %d", version)
+	}
+	if length > len(data) || length < 12 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	var jsonChunk, binChunk []byte
+	rest := data[12:length]
+	for len(rest) >= 8 {
+		size := int(binary.LittleEndian.Uint32(rest))
+		typ := binary.LittleEndian.Uint32(rest[4:])
+		if size > len(rest) - 8 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		chunk := rest[8:8 + size]
+		switch {
+		case typ == glbChunkJSON && jsonChunk == nil:
+			jsonChunk = chunk
+		case typ == glbChunkBIN && binChunk == nil:
+			binChunk = chunk
+		}
+		rest = rest[8 + size:]
+	}
+	if jsonChunk == nil {
+		return nil, nil, errors.New("physics: glb without JSON chunk")
+	}
+	return jsonChunk, binChunk, nil
+}
+
+func (g *gltfReader) loadBuffers(binChunk []byte, dir string) (error) {
+	g.buffers = make([][]byte, len(g.doc.Buffers))
+	for i, b := range g.doc.Buffers {
+		var data []byte
+		var err error
+		switch {
+		case b.URI == "":
+			if i != 0 || binChunk == nil {
+				return ErrGLTFRange
+			}
+			data = binChunk
+		case strings.HasPrefix(b.URI, "data:"):
+			comma := strings.IndexByte(b.URI, ',')
+			if comma < 0 || !strings.HasSuffix(b.URI[:comma], ";base64") {
+				return errors.New("physics: gltf data uri is not base64")
+			}
+			data, err = base64.StdEncoding.DecodeString(b.URI[comma + 1:])
+		case strings.Contains(b.URI, "://") || dir == "":
+			return ErrGLTFExternal
+		default:
+			name, uerr := url.PathUnescape(b.URI)
+			if uerr != nil {
+				return uerr
+			}
+			// only files inside dir, no absolute paths or ..
+			name = filepath.FromSlash(name)
+			if !filepath.IsLocal(name) {
+				return ErrGLTFExternal
+			}
+			data, err = os.ReadFile(filepath.Join(dir, name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(data) < b.ByteLength {
+			return io.ErrUnexpectedEOF
+		}
+		g.buffers[i] = data
+	}
+	return nil
+}
+
+// bytes of an accessor and the distance between elements
+func (g *gltfReader) accessor(i int, typ string, comps int) (*gltfAccessor, []byte, int, error) {
+	if i < 0 || i >= len(g.doc.Accessors) {
+		return nil, nil, 0, ErrGLTFRange
+	}
+	acc := &g.doc.Accessors[i]
+	if acc.Type != typ {
+		return nil, nil, 0, fmt.Errorf("physics: gltf accessor %d is %s, want %s", i, acc.Type, typ)
+	}
+	if acc.Sparse != nil || acc.BufferView == nil {
+		return nil, nil, 0, fmt.Errorf("physics: gltf accessor %d: sparse or bufferless accessors are not supported", i)
+	}
+	if *acc.BufferView < 0 || *acc.BufferView >= len(g.doc.BufferViews) {
+		return nil, nil, 0, ErrGLTFRange
+	}
+	bv := &g.doc.BufferViews[*acc.BufferView]
+	if bv.Buffer < 0 || bv.Buffer >= len(g.buffers) {
+		return nil, nil, 0, ErrGLTFRange
+	}
+
+	var size int
+	switch acc.ComponentType {
+	case gltfUnsignedByte:
+		size = 1
+	case gltfUnsignedShort:
+		size = 2
+	case gltfUnsignedInt, gltfFloat:
+		size = 4
+	default:
+		return nil, nil, 0, fmt.Errorf("physics: gltf accessor %d: unsupported component type %d", i, acc.ComponentType)
+	}
+	elem := size * comps
+	stride := bv.ByteStride
+	if stride == 0 {
+		stride = elem
+	}
+	if stride < elem {
+		return nil, nil, 0, ErrGLTFRange
+	}
+
+	// compare by subtraction and division, the sizes come from the file and may overflow int
+	buf := g.buffers[bv.Buffer]
+	if bv.ByteOffset < 0 || bv.ByteLength < 0 || bv.ByteOffset > len(buf) || bv.ByteLength > len(buf) - bv.ByteOffset || acc.ByteOffset < 0 || acc.Count < 0 {
+		return nil, nil, 0, ErrGLTFRange
+	}
+	view := buf[bv.ByteOffset:bv.ByteOffset + bv.ByteLength]
+	if acc.ByteOffset > len(view) {
+		return nil, nil, 0, ErrGLTFRange
+	}
+	if avail := len(view) - acc.ByteOffset; acc.Count > 0 && (avail < elem || acc.Count - 1 > (avail - elem) / stride) {
+		return nil, nil, 0, ErrGLTFRange
+	}
+	return acc, view[acc.ByteOffset:], stride, nil
+}
+
+func (g *gltfReader) positions(i int) ([]Vec3, error) {
+	acc, data, stride, err := g.accessor(i, "VEC3", 3)
+	if err != nil {
+		return nil, err
+	}
+	if acc.ComponentType != gltfFloat {
+		return nil, fmt.Errorf("physics: gltf accessor %d: positions must be float", i)
+	}
+	vs := make([]Vec3, acc.Count)
+	for k := range vs {
+		e := data[k * stride:]
+		for c := 0; c < 3; c++ {
+			vs[k][c] = Number(math.Float32frombits(binary.LittleEndian.Uint32(e[4 * c:])))
+		}
+	}
+	return vs, nil
+}
+
+func (g *gltfReader) indices(i int) ([]int, error) {
+	acc, data, stride, err := g.accessor(i, "SCALAR", 1)
+	if err != nil {
+		return nil, err
+	}
+	is := make([]int, acc.Count)
+	for k := range is {
+		e := data[k * stride:]
+		switch acc.ComponentType {
+		case gltfUnsignedByte:
+			is[k] = int(e[0])
+		case gltfUnsignedShort:
+			is[k] = int(binary.LittleEndian.Uint16(e))
+		case gltfUnsignedInt:
+			is[k] = int(binary.LittleEndian.Uint32(e))
+		default:
+			return nil, fmt.Errorf("physics: gltf accessor %d: indices must be unsigned integers", i)
+		}
+	}
+	return is, nil
+}
+
+// append the triangles of a mesh, with positions mapped by xf, to m
+// flip reverses the winding, for transforms that mirror
+func (g *gltfReader) appendMesh(m *Mesh, mi int, xf func(*Vec3), flip bool) (error) {
+	if mi < 0 || mi >= len(g.doc.Meshes) {
+		return ErrGLTFRange
+	}
+	for _, prim := range g.doc.Meshes[mi].Primitives {
+		mode := gltfTriangles
+		if prim.Mode != nil {
+			mode = *prim.Mode
+		}
+		if mode != gltfTriangles && mode != gltfTriangleStrip && mode != gltfTriangleFan {
+			continue // points and lines do not collide
+		}
+
+		pi, ok := prim.Attributes["POSITION"]
+		if !ok {
+			continue
+		}
+		vs, err := g.positions(pi)
+		if err != nil {
+			return err
+		}
+
+		var is []int
+		if prim.Indices != nil {
+			if is, err = g.indices(*prim.Indices); err != nil {
+				return err
+			}
+		} else {
+			is = make([]int, len(vs))
+			for k := range is {
+				is[k] = k
+			}
+		}
+		for _, k := range is {
+			if k >= len(vs) {
+				return ErrGLTFRange
+			}
+		}
+
+		base := len(m.Vertices)
+		for k := range vs {
+			xf(&vs[k])
+		}
+		m.Vertices = append(m.Vertices, vs...)
+
+		start := len(m.Indices)
+		switch mode {
+		case gltfTriangles:
+			for k := 0; k + 2 < len(is); k += 3 {
+				m.Indices = append(m.Indices, base + is[k], base + is[k + 1], base + is[k + 2])
+			}
+		case gltfTriangleStrip:
+			for k := 0; k + 2 < len(is); k++ {
+				if k % 2 == 0 {
+					m.Indices = append(m.Indices, base + is[k], base + is[k + 1], base + is[k + 2])
+				} else {
+					m.Indices = append(m.Indices, base + is[k + 1], base + is[k], base + is[k + 2])
+				}
+			}
+		case gltfTriangleFan:
+			for k := 1; k + 1 < len(is); k++ {
+				m.Indices = append(m.Indices, base + is[0], base + is[k], base + is[k + 1])
+			}
+		}
+		if flip {
+			for k := start; k + 2 < len(m.Indices); k += 3 {
+				m.Indices[k + 1], m.Indices[k + 2] = m.Indices[k + 2], m.Indices[k + 1]
+			}
+		}
+	}
+	return nil
+}
+
+// apply the local transform of a node to p
+func (n *gltfNode) apply(p *Vec3) {
+	if len(n.Matrix) == 16 {
+		m := n.Matrix
+		x, y, z := float64(p[0]), float64(p[1]), float64(p[2])
+		// products rounded on their own so they are not fused into FMA, see Determinism in the README
+		p[0] = Number(float64(m[0] * x) + float64(m[4] * y) + float64(m[8] * z) + m[12])
+		p[1] = Number(float64(m[1] * x) + float64(m[5] * y) + float64(m[9] * z) + m[13])
+		p[2] = Number(float64(m[2] * x) + float64(m[6] * y) + float64(m[10] * z) + m[14])
+		return
+	}
+
+	if len(n.Scale) == 3 {
+		p[0] *= Number(n.Scale[0])
+		p[1] *= Number(n.Scale[1])
+		p[2] *= Number(n.Scale[2])
+	}
+	tf := Transform{ Pos: NewVec3(), Rot: NewQuat() }
+	if len(n.Rotation) == 4 {
+		tf.Rot.Set(Number(n.Rotation[0]), Number(n.Rotation[1]), Number(n.Rotation[2]), Number(n.Rotation[3]))
+	}
+	if len(n.Translation) == 3 {
+		tf.Pos.Set(Number(n.Translation[0]), Number(n.Translation[1]), Number(n.Translation[2]))
+	}
+	tf.PointToWorld(p, p)
+}
+
+// whether the local transform of a node mirrors, its linear part has a negative determinant
+func (n *gltfNode) mirrors() (bool) {
+	if len(n.Matrix) == 16 {
+		m := n.Matrix
+		det := float64(m[0] * (float64(m[5] * m[10]) - float64(m[9] * m[6]))) -
+			float64(m[4] * (float64(m[1] * m[10]) - float64(m[9] * m[2]))) +
+			float64(m[8] * (float64(m[1] * m[6]) - float64(m[5] * m[2])))
+		return det < 0
+	}
+	if len(n.Scale) == 3 {
+		return n.Scale[0] * n.Scale[1] * n.Scale[2] < 0 // rotations do not mirror
+	}
+	return false
+}
+
+func (g *gltfReader) meshes() ([]*Mesh, error) {
+	var roots []int
+	if len(g.doc.Scenes) > 0 {
+		s := 0
+		if g.doc.Scene != nil {
+			s = *g.doc.Scene
+		}
+		if s < 0 || s >= len(g.doc.Scenes) {
+			return nil, ErrGLTFRange
+		}
+		roots = g.doc.Scenes[s].Nodes
+	} else {
+		// no scene: every node that is nobody's child
+		child := make([]bool, len(g.doc.Nodes))
+		for _, n := range g.doc.Nodes {
+			for _, c := range n.Children {
+				if c >= 0 && c < len(child) {
+					child[c] = true
+				}
+			}
+		}
+		for i, c := range child {
+			if !c {
+				roots = append(roots, i)
+			}
+		}
+	}
+
+	var meshes []*Mesh
+	visited := make([]bool, len(g.doc.Nodes))
+	var walk func(ni int, chain []int, flip bool) (error)
+	walk = func(ni int, chain []int, flip bool) (error) {
+		if ni < 0 || ni >= len(g.doc.Nodes) || visited[ni] {
+			return ErrGLTFRange // bad index, a cycle or a node with two parents
+		}
+		visited[ni] = true
+		chain = append(chain, ni)
+		n := &g.doc.Nodes[ni]
+		flip = flip != n.mirrors()
+
+		if n.Mesh != nil {
+			m := &Mesh{ Name: n.Name }
+			if m.Name == "" && *n.Mesh >= 0 && *n.Mesh < len(g.doc.Meshes) {
+				m.Name = g.doc.Meshes[*n.Mesh].Name
+			}
+			xf := func(p *Vec3) {
+				for k := len(chain) - 1; k >= 0; k-- {
+					g.doc.Nodes[chain[k]].apply(p)
+				}
+			}
+			if err := g.appendMesh(m, *n.Mesh, xf, flip); err != nil {
+				return err
+			}
+			if len(m.Indices) > 0 {
+				meshes = append(meshes, m)
+			}
+		}
+
+		for _, c := range n.Children {
+			if err := walk(c, chain[:len(chain):len(chain)], flip); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, r := range roots {
+		if err := walk(r, nil, false); err != nil {
+			return nil, err
+		}
+	}
+	return meshes, nil
+}
+
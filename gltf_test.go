@@ -0,0 +1,201 @@
+package physics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// one triangle, indexed with uint16
+func testGLTFBuffer() ([]byte) {
+	var b bytes.Buffer
+	for _, f := range []float32{ 0, 0, 0, 1, 0, 0, 0, 1, 0 } {
+		binary.Write(&b, binary.LittleEndian, f)
+	}
+	for _, i := range []uint16{ 0, 1, 2, 0 } { // padded to 4 bytes
+		binary.Write(&b, binary.LittleEndian, i)
+	}
+	return b.Bytes()
+}
+
+func testGLTFJSON(uri string) (string) {
+	s := math.Sqrt(0.5)
+	buffer := `{"byteLength": 44}`
+	if uri != "" {
+		buffer = fmt.Sprintf(`{"byteLength": 44, "uri": %q}`, uri)
+	}
+	return fmt.Sprintf(`{
+		"asset": {"version": "2.0"},
+		"scene": 0,
+		"scenes": [{"nodes": [0]}],
+		"nodes": [
+			{"name": "root", "translation": [10, 0, 0], "rotation": [0, 0, %v, %v], "children": [1]},
+			{"name": "child", "mesh": 0, "scale": [2, 2, 2]}
+		],
+		"meshes": [{"primitives": [{"attributes": {"POSITION": 0}, "indices": 1}]}],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC3"},
+			{"bufferView": 1, "componentType": 5123, "count": 3, "type": "SCALAR"}
+		],
+		"bufferViews": [
+			{"buffer": 0, "byteOffset": 0, "byteLength": 36},
+			{"buffer": 0, "byteOffset": 36, "byteLength": 6}
+		],
+		"buffers": [%s]
+	}`, s, s, buffer)
+}
+
+func checkGLTFMeshes(t *testing.T, meshes []*Mesh) {
+	if len(meshes) != 1 {
+		t.Fatal("Error reading glTF meshes, got ", len(meshes))
+	}
+	m := meshes[0]
+	if m.Name != "child" || m.TriangleCount() != 1 {
+		t.Error("Error reading glTF mesh, got ", m)
+	}
+
+	// scaled by 2, rotated 90 degrees around z, moved 10 along x
+	want := []*Vec3{ NewVec3().Set(10, 0, 0), NewVec3().Set(10, 2, 0), NewVec3().Set(8, 0, 0) }
+	for i, w := range want {
+		if !m.Vertices[m.Indices[i]].AlmostEquals(w) {
+			t.Error("Error transforming glTF vertex ", i, m.Vertices[m.Indices[i]], w)
+		}
+	}
+}
+
+func TestReadGLTFDataURI(t *testing.T) {
+
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(testGLTFBuffer())
+	meshes, err := ReadGLTF(bytes.NewReader([]byte(testGLTFJSON(uri))), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGLTFMeshes(t, meshes)
+
+	_, err = ReadGLTF(bytes.NewReader([]byte(testGLTFJSON("https://example.com/mesh.bin"))), "")
+	if err != ErrGLTFExternal {
+		t.Error("network buffers should be refused, got ", err)
+	}
+
+}
+
+func TestReadGLB(t *testing.T) {
+
+	js := []byte(testGLTFJSON(""))
+	for len(js) % 4 != 0 {
+		js = append(js, ' ')
+	}
+	bin := testGLTFBuffer()
+
+	var b bytes.Buffer
+	b.WriteString("glTF")
+	binary.Write(&b, binary.LittleEndian, uint32(2))
+	binary.Write(&b, binary.LittleEndian, uint32(12 + 8 + len(js) + 8 + len(bin)))
+	binary.Write(&b, binary.LittleEndian, uint32(len(js)))
+	binary.Write(&b, binary.LittleEndian, uint32(glbChunkJSON))
+	b.Write(js)
+	binary.Write(&b, binary.LittleEndian, uint32(len(bin)))
+	binary.Write(&b, binary.LittleEndian, uint32(glbChunkBIN))
+	b.Write(bin)
+
+	meshes, err := ReadGLTF(&b, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGLTFMeshes(t, meshes)
+
+}
+
+func TestReadGLTFFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "models")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{ dir, sub } {
+		if err := os.WriteFile(filepath.Join(d, "mesh.bin"), testGLTFBuffer(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	meshes, err := ReadGLTF(strings.NewReader(testGLTFJSON("mesh.bin")), sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGLTFMeshes(t, meshes)
+
+	for _, uri := range []string{ "../mesh.bin", "models/../../mesh.bin", filepath.ToSlash(filepath.Join(dir, "mesh.bin")) } {
+		if _, err := ReadGLTF(strings.NewReader(testGLTFJSON(uri)), sub); err != ErrGLTFExternal {
+			t.Error("buffers outside dir should be refused, got ", uri, err)
+		}
+	}
+
+}
+
+func TestReadGLTFHugeCount(t *testing.T) {
+
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(testGLTFBuffer())
+	for _, count := range []string{ "768614336404564651", "4", "-1" } {
+		js := strings.Replace(testGLTFJSON(uri), `"count": 3, "type": "VEC3"`, `"count": ` + count + `, "type": "VEC3"`, 1)
+		if _, err := ReadGLTF(strings.NewReader(js), ""); err != ErrGLTFRange {
+			t.Error("accessor count beyond its buffer view should fail, got ", count, err)
+		}
+	}
+
+}
+
+func TestReadGLTFSharedChildren(t *testing.T) {
+
+	// node i lists i+1 twice, walking every path would take 2^39 steps
+	var nodes []string
+	for i := 0; i < 39; i++ {
+		nodes = append(nodes, fmt.Sprintf(`{"children": [%d, %d]}`, i + 1, i + 1))
+	}
+	nodes = append(nodes, `{"mesh": 0}`)
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(testGLTFBuffer())
+	js := testGLTFJSON(uri)
+	start := strings.LastIndex(js, `"nodes": [`) + len(`"nodes": [`)
+	end := strings.Index(js, `"meshes"`)
+	js = js[:start] + strings.Join(nodes, ",") + "],\n\t\t" + js[end:]
+
+	if _, err := ReadGLTF(strings.NewReader(js), ""); err != ErrGLTFRange {
+		t.Error("a node reached twice should fail, got ", err)
+	}
+
+}
+
+func TestReadGLTFMirror(t *testing.T) {
+
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(testGLTFBuffer())
+	normalZ := func(m *Mesh) (Number) {
+		var e1, e2, n Vec3
+		m.Vertices[m.Indices[1]].VSub(&m.Vertices[m.Indices[0]], &e1)
+		m.Vertices[m.Indices[2]].VSub(&m.Vertices[m.Indices[0]], &e2)
+		e1.Cross(&e2, &n)
+		return n[2]
+	}
+
+	for _, c := range []struct{ child string; z Number }{
+		{ `"scale": [2, 2, 2]`, 1 },
+		{ `"scale": [2, 2, -2]`, -1 },
+		{ `"scale": [-2, -2, 2]`, 1 },
+		{ `"matrix": [2,0,0,0, 0,2,0,0, 0,0,-2,0, 0,0,0,1]`, -1 },
+	} {
+		js := strings.Replace(testGLTFJSON(uri), `"scale": [2, 2, 2]`, c.child, 1)
+		meshes, err := ReadGLTF(strings.NewReader(js), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if z := normalZ(meshes[0]); z * c.z <= 0 {
+			t.Error("Error winding mirrored triangle, got normal z ", z, " for ", c.child)
+		}
+	}
+
+}
@@ -0,0 +1,119 @@
+package physics
+
+import (
+	"math"
+)
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64 = 1099511628211
+)
+
+/**
+ * FNV-1a hash over the exact bits of simulation state, to check that lockstep peers are in sync.
+ * Equal values with different bits (0 and -0, NaNs) hash differently on purpose.
+ * @class StateHash
+ */
+type StateHash uint64
+
+func NewStateHash() (*StateHash) {
+	h := StateHash(fnvOffset64)
+	return &h
+}
+
+func (h *StateHash) writeUint64(u uint64) {
+	x := uint64(*h)
+	for i := 0; i < 8; i++ {
+		x ^= u & 0xff
+		x *= fnvPrime64
+		u >>= 8
+	}
+	*h = StateHash(x)
+}
+
+/**
+ * @method number
+ * @param {Number} n
+ * @return {StateHash} this
+ */
+func (h *StateHash) Number(n Number) (*StateHash) {
+	h.writeUint64(math.Float64bits(float64(n)))
+	return h
+}
+
+/**
+ * @method int
+ * @param {int} n
+ * @return {StateHash} this
+ */
+func (h *StateHash) Int(n int) (*StateHash) {
+	h.writeUint64(uint64(n))
+	return h
+}
+
+/**
+ * @method vec3
+ * @param {Vec3} v
+ * @return {StateHash} this
+ */
+func (h *StateHash) Vec3(v *Vec3) (*StateHash) {
+	h.Number(v[0]).Number(v[1]).Number(v[2])
+	return h
+}
+
+/**
+ * @method quat
+ * @param {Quaternion} q
+ * @return {StateHash} this
+ */
+func (h *StateHash) Quat(q *Quat) (*StateHash) {
+	h.Number(q[0]).Number(q[1]).Number(q[2]).Number(q[3])
+	return h
+}
+
+/**
+ * @method transform
+ * @param {Transform} tf
+ * @return {StateHash} this
+ */
+func (h *StateHash) Transform(tf *Transform) (*StateHash) {
+	return h.Vec3(tf.Pos).Quat(tf.Rot)
+}
+
+/**
+ * @method sum64
+ * @return {uint64}
+ */
+func (h *StateHash) Sum64() (uint64) {
+	return uint64(*h)
+}
+
+/**
+ * Hash the dynamic state: time, particle positions, velocities and pins, and constraint rest values.
+ * @method stateHash
+ * @param {StateHash} h
+ * @return {StateHash} h
+ */
+func (sb *SoftBody) StateHash(h *StateHash) (*StateHash) {
+	h.Number(sb.Time).Int(len(sb.Particles))
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		h.Vec3(&p.Position).Vec3(&p.Velocity).Number(p.InvMass)
+		if p.Pinned {
+			h.Int(1)
+		} else {
+			h.Int(0)
+		}
+	}
+	for i := range sb.Distances {
+		h.Number(sb.Distances[i].RestLength)
+	}
+	for i := range sb.Bendings {
+		h.Number(sb.Bendings[i].RestHeight)
+	}
+	for i := range sb.Volumes {
+		h.Number(sb.Volumes[i].RestVolume).Number(sb.Volumes[i].Pressure)
+	}
+	return h
+}
+
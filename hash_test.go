@@ -0,0 +1,49 @@
+package physics
+
+import (
+	"testing"
+)
+
+func TestStateHash(t *testing.T) {
+
+	a := NewStateHash().Vec3(NewVec3().Set(1, 2, 3)).Quat(NewQuat()).Sum64()
+	b := NewStateHash().Vec3(NewVec3().Set(1, 2, 3)).Quat(NewQuat()).Sum64()
+	if a != b {
+		t.Error("equal state should hash equal, got ", a, b)
+	}
+
+	c := NewStateHash().Vec3(NewVec3().Set(1, 2, 3 + PRECISION)).Quat(NewQuat()).Sum64()
+	if a == c {
+		t.Error("different state should hash different, got ", a, c)
+	}
+
+	var negZero Number
+	negZero = -negZero
+	z0 := NewStateHash().Number(0).Sum64()
+	z1 := NewStateHash().Number(negZero).Sum64()
+	if z0 == z1 {
+		t.Error("0 and -0 should hash different, got ", z0, z1)
+	}
+
+}
+
+func TestSoftBodyDeterministic(t *testing.T) {
+
+	run := func() (uint64) {
+		cloth := NewCloth(NewVec3().Set(0, 2, 0), NewVec3().Set(1, 0, 0), NewVec3().Set(0, 0, 1), 8, 8, 1, 0.9)
+		cloth.Gravity.Set(0, -9.82, 0)
+		cloth.Fields = append(cloth.Fields, &Wind{ Velocity: Vec3{ 0, 0, 3 }, Drag: 0.1, Turbulence: 0.5, Frequency: 2 })
+		cloth.Colliders = append(cloth.Colliders, &SphereCollider{ Center: Vec3{ 0.5, 1, 0.5 }, Radius: 0.3 })
+		cloth.Pin(0)
+		for i := 0; i < 120; i++ {
+			cloth.Step(1.0 / 60)
+		}
+		return cloth.StateHash(NewStateHash()).Sum64()
+	}
+
+	if a, b := run(), run(); a != b {
+		t.Error("identical runs should hash equal, got ", a, b)
+	}
+
+}
+
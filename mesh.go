@@ -0,0 +1,166 @@
+package physics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/**
+ * Triangle soup for collision geometry, e.g. to build a Trimesh or ConvexPolyhedron from.
+ * @class Mesh
+ * @param {string} Name Object or node name from the file
+ * @param {[]Vec3} Vertices
+ * @param {[]int} Indices Three per triangle, counter-clockwise
+ */
+type Mesh struct {
+	Name string
+	Vertices []Vec3
+	Indices []int
+}
+
+/**
+ * @method triangleCount
+ * @return {int}
+ */
+func (m *Mesh) TriangleCount() (int) {
+	return len(m.Indices) / 3
+}
+
+/**
+ * The triangles as index triples, e.g. for SoftBody.AddVolumeConstraint.
+ * @method triangles
+ * @return {[][3]int}
+ */
+func (m *Mesh) Triangles() ([][3]int) {
+	tris := make([][3]int, m.TriangleCount())
+	for i := range tris {
+		tris[i] = [3]int{ m.Indices[3 * i], m.Indices[3 * i + 1], m.Indices[3 * i + 2] }
+	}
+	return tris
+}
+
+// collects faces indexing a shared vertex list into a mesh with its own vertices
+type meshBuilder struct {
+	mesh *Mesh
+	local map[int]int
+}
+
+func newMeshBuilder(name string) (*meshBuilder) {
+	return &meshBuilder{ mesh: &Mesh{ Name: name }, local: make(map[int]int) }
+}
+
+func (b *meshBuilder) vertex(global int, vertices []Vec3) (int) {
+	i, ok := b.local[global]
+	if !ok {
+		i = len(b.mesh.Vertices)
+		b.local[global] = i
+		b.mesh.Vertices = append(b.mesh.Vertices, vertices[global])
+	}
+	return i
+}
+
+/**
+ * Read a Wavefront OBJ file. Every "o" statement starts a new Mesh, polygons are triangulated as fans.
+ * Normals, texture coordinates and materials are ignored.
+ * @method readOBJ
+ * @param {io.Reader} r
+ * @return {[]Mesh}
+ */
+func ReadOBJ(r io.Reader) ([]*Mesh, error) {
+	var vertices []Vec3
+	var meshes []*Mesh
+	cur := newMeshBuilder("")
+
+	flush := func() {
+		if len(cur.mesh.Indices) > 0 {
+			meshes = append(meshes, cur.mesh)
+		}
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64 * 1024), 16 * 1024 * 1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "o":
+			flush()
+			cur = newMeshBuilder(strings.Join(fields[1:], " "))
+
+		case "v":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("physics: obj line %d: vertex needs 3 coordinates", line)
+			}
+			var v Vec3
+			for k := 0; k < 3; k++ {
+				n, err := strconv.ParseFloat(fields[k + 1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("physics: obj line %d: %v", line, err)
+				}
+				v[k] = Number(n)
+			}
+			vertices = append(vertices, v)
+
+		case "f":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("physics: obj line %d: face needs 3 vertices", line)
+			}
+			face := make([]int, 0, len(fields) - 1)
+			for _, f := range fields[1:] {
+				if i := strings.IndexByte(f, '/'); i >= 0 {
+					f = f[:i]
+				}
+				idx, err := strconv.Atoi(f)
+				if err != nil {
+					return nil, fmt.Errorf("physics: obj line %d: %v", line, err)
+				}
+				if idx < 0 {
+					idx += len(vertices) // relative to the last vertex
+				} else {
+					idx-- // 1-based
+				}
+				if idx < 0 || idx >= len(vertices) {
+					return nil, fmt.Errorf("physics: obj line %d: vertex index out of range", line)
+				}
+				face = append(face, cur.vertex(idx, vertices))
+			}
+			for k := 2; k < len(face); k++ {
+				cur.mesh.Indices = append(cur.mesh.Indices, face[0], face[k - 1], face[k])
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return meshes, nil
+}
+
+/**
+ * @method loadOBJ
+ * @param {string} path
+ * @return {[]Mesh}
+ */
+func LoadOBJ(path string) ([]*Mesh, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadOBJ(f)
+}
+
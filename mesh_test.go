@@ -0,0 +1,52 @@
+package physics
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOBJ = `# two objects
+o quad
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vn 0 0 1
+f 1//1 2//1 3//1 4//1
+
+o tri
+v 5 5 5
+v 6 5 5
+v 5 6 5
+f -3/1 -2/2 -1/3
+`
+
+func TestReadOBJ(t *testing.T) {
+
+	meshes, err := ReadOBJ(strings.NewReader(testOBJ))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meshes) != 2 {
+		t.Fatal("Error reading OBJ objects, got ", len(meshes))
+	}
+
+	quad := meshes[0]
+	if quad.Name != "quad" || len(quad.Vertices) != 4 || quad.TriangleCount() != 2 {
+		t.Error("Error reading OBJ quad, got ", quad)
+	}
+	if tris := quad.Triangles(); tris[0] != [3]int{ 0, 1, 2 } || tris[1] != [3]int{ 0, 2, 3 } {
+		t.Error("Error triangulating OBJ quad, got ", tris)
+	}
+
+	tri := meshes[1]
+	if tri.Name != "tri" || len(tri.Vertices) != 3 || !tri.Vertices[tri.Indices[0]].IsEquals(NewVec3().Set(5, 5, 5)) {
+		t.Error("Error reading OBJ relative indices, got ", tri)
+	}
+
+	if _, err := ReadOBJ(strings.NewReader("v 0 0 0\nf 1 2 3\n")); err == nil {
+		t.Error("out of range face should fail")
+	}
+
+}
+
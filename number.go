@@ -5,31 +5,43 @@ import (
 )
 
 const (
-	PRECISION = 1e-6
+	precision32 = 1e-4 // float32 has about 7 significant digits
+	precision64 = 1e-6
 )
 
-type Number = float64
+// Float is the element type of Vec3T and QuatT.
+type Float interface {
+	float32 | float64
+}
+
+func precisionOf[T Float]() (T) {
+	var n T
+	if _, ok := any(n).(float32); ok {
+		return precision32
+	}
+	return precision64
+}
 
-func almostZero(n Number) (bool) {
-	if math.Abs(float64(n)) > PRECISION {
+func almostZero[T Float](n T) (bool) {
+	if math.Abs(float64(n)) > float64(precisionOf[T]()) {
 		return false
 	}
 	return true
 }
 
-func almostEquals(n, n1 Number) (bool) {
-	if math.Abs(float64(n - n1)) > PRECISION {
+func almostEquals[T Float](n, n1 T) (bool) {
+	if math.Abs(float64(n - n1)) > float64(precisionOf[T]()) {
 		return false
 	}
 	return true
 }
 
-func clamp(value, min, max Number) (Number) {
+func clamp[T Float](value, min, max T) (T) {
 	val := math.Max( float64(min), math.Min( float64(max), float64(value) ) )
-	return Number(val)
+	return T(val)
 }
 
-func clampF(value Number, min float64, max float64) (float64) {
+func clampF[T Float](value T, min float64, max float64) (float64) {
 	val := math.Max( min, math.Min( max, float64(value) ) )
 	return val
 }
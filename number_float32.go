@@ -0,0 +1,9 @@
+//go:build physics_float32
+
+package physics
+
+const (
+	PRECISION = precision32
+)
+
+type Number = float32
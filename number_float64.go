@@ -0,0 +1,9 @@
+//go:build !physics_float32
+
+package physics
+
+const (
+	PRECISION = precision64
+)
+
+type Number = float64
@@ -0,0 +1,78 @@
+package physics
+
+/**
+ * For pooling objects that can be reused, like cannon.js Pool.
+ * Get returns a released object when there is one, so its value is whatever it was released with.
+ * @class Pool
+ * @param {func} New Constructs a new object when the pool is empty
+ */
+type Pool[E any] struct {
+	objects []*E
+	New func() (*E)
+}
+
+type Vec3Pool = Pool[Vec3]
+type QuatPool = Pool[Quat]
+
+func NewVec3Pool() (*Vec3Pool) {
+	return &Vec3Pool{ New: NewVec3 }
+}
+
+func NewQuatPool() (*QuatPool) {
+	return &QuatPool{ New: NewQuat }
+}
+
+/**
+ * Release objects back into the pool.
+ * @method release
+ * @param {*E} objects
+ */
+func (p *Pool[E]) Release(objects ...*E) {
+	p.objects = append(p.objects, objects...)
+}
+
+/**
+ * Get an object
+ * @method get
+ * @return {*E}
+ */
+func (p *Pool[E]) Get() (*E) {
+	n := len(p.objects)
+	if n == 0 {
+		if p.New == nil {
+			return new(E)
+		}
+		return p.New()
+	}
+	obj := p.objects[n - 1]
+	p.objects[n - 1] = nil
+	p.objects = p.objects[:n - 1]
+	return obj
+}
+
+/**
+ * Fill or shrink the pool to size objects, call up front so Get does not allocate.
+ * @method resize
+ * @param {int} size
+ */
+func (p *Pool[E]) Resize(size int) {
+	for len(p.objects) > size {
+		p.objects[len(p.objects) - 1] = nil
+		p.objects = p.objects[:len(p.objects) - 1]
+	}
+	for len(p.objects) < size {
+		if p.New == nil {
+			p.objects = append(p.objects, new(E))
+		} else {
+			p.objects = append(p.objects, p.New())
+		}
+	}
+}
+
+/**
+ * Number of objects available.
+ * @method len
+ */
+func (p *Pool[E]) Len() (int) {
+	return len(p.objects)
+}
@@ -0,0 +1,50 @@
+package physics
+
+import (
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+
+	p := NewQuatPool()
+	q := p.Get()
+	if !q.IsEquals(NewQuat()) {
+		t.Error("new pooled Quat should be identity, got ", q)
+	}
+	p.Release(q)
+	if p.Len() != 1 || p.Get() != q {
+		t.Error("Error reusing released object")
+	}
+
+	vp := NewVec3Pool()
+	vp.Resize(4)
+	allocs := testing.AllocsPerRun(100, func() {
+		a, b := vp.Get(), vp.Get()
+		a.Set(1, 2, 3).Cross(NewVec3().Set(0, 1, 0), b)
+		vp.Release(a, b)
+	})
+	if allocs != 0 || vp.Len() != 4 {
+		t.Error("Get/Release of a filled pool should not allocate, got ", allocs, vp.Len())
+	}
+
+}
+
+func TestHotPathAllocs(t *testing.T) {
+
+	u, v := NewVec3().Set(1, 0, 0), NewVec3().Set(-1, 0, 0)
+	var t1, t2, r Vec3
+	var q Quat
+	tf := Transform{ Pos: NewVec3().Set(1, 2, 3), Rot: NewQuat().SetFromAxisAngle(NewVec3().Set(0, 1, 0), 0.5) }
+
+	allocs := testing.AllocsPerRun(100, func() {
+		u.Tangents(&t1, &t2)
+		q.SetFromVectors(u, v)
+		q.SetFromVectors(u, &t1)
+		tf.PointToLocal(u, &r)
+		u.IsAntiparallelTo(v)
+	})
+	if allocs != 0 {
+		t.Error("hot paths should not allocate with targets given, got ", allocs)
+	}
+
+}
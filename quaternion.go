@@ -15,7 +15,9 @@ const (
 	XZY // 5
 )
 
-type Quat [4]Number // order: x, y, z, w
+type QuatT[T Float] [4]T // order: x, y, z, w
+
+type Quat = QuatT[Number]
 
 /*func NewQuat(x, y, z, w Number) (*Quat) {
 	q := &Quat{ x, y, z, w }
@@ -27,6 +29,11 @@ func NewQuat() (*Quat) {
 	return q
 }
 
+func NewQuatT[T Float]() (*QuatT[T]) {
+	q := &QuatT[T]{ 0, 0, 0, 1 }
+	return q
+}
+
 
 /**
  * Set the value of the quaternion.
@@ -36,7 +43,7 @@ func NewQuat() (*Quat) {
  * @param {Number} z
  * @param {Number} w
  */
-func (q *Quat) Set(x, y, z, w Number) (*Quat) {
+func (q *QuatT[T]) Set(x, y, z, w T) (*QuatT[T]) {
 	q[0], q[1], q[2], q[3] = x, y, z, w
 	return q
 }
@@ -45,8 +52,8 @@ func (q *Quat) Set(x, y, z, w Number) (*Quat) {
  * @method clone
  * @return {Quaternion}
  */
-func (q *Quat) Clone() (*Quat) {
-	return &Quat{ q[0], q[1], q[2], q[3] }
+func (q *QuatT[T]) Clone() (*QuatT[T]) {
+	return &QuatT[T]{ q[0], q[1], q[2], q[3] }
 }
 
 /**
@@ -55,7 +62,7 @@ func (q *Quat) Clone() (*Quat) {
  * @param {Quaternion} source
  * @return {Quaternion} this
  */
-func (q *Quat) Copy(source *Quat) (*Quat) {
+func (q *QuatT[T]) Copy(source *QuatT[T]) (*QuatT[T]) {
 	q[0], q[1], q[2], q[3] = source[0], source[1], source[2], source[3]
 	return q
 }
@@ -67,7 +74,7 @@ func (q *Quat) Copy(source *Quat) (*Quat) {
  * @param {Vec3} v
  * @return bool
  */
-func (q *Quat) IsEquals(q1 *Quat) (bool) {
+func (q *QuatT[T]) IsEquals(q1 *QuatT[T]) (bool) {
 	if (q[0] == q1[0]) && (q[1] == q1[1]) && (q[2] == q1[2]) && (q[3] == q1[3]) {
 		return true
 	}
@@ -80,7 +87,7 @@ func (q *Quat) IsEquals(q1 *Quat) (bool) {
  * @param {Vec3} v
  * @return bool
  */
-func (q *Quat) AlmostEquals(q1 *Quat) (bool) {
+func (q *QuatT[T]) AlmostEquals(q1 *QuatT[T]) (bool) {
 	if almostEquals(q[0], q1[0]) && almostEquals(q[1], q1[1]) && almostEquals(q[2], q1[2]) && almostEquals(q[3], q1[3]) {
 		return true
 	}
@@ -93,13 +100,13 @@ func (q *Quat) AlmostEquals(q1 *Quat) (bool) {
  * @param {Vec3} axis
  * @param {Number} angle in radians
  */
-func (q *Quat) SetFromAxisAngle(axis *Vec3, angle Number) (*Quat) {
-	sin, c := math.Sincos(float64(angle) * 0.5)
-	s := Number(sin)
+func (q *QuatT[T]) SetFromAxisAngle(axis *Vec3T[T], angle T) (*QuatT[T]) {
+	sin, c := detSincos(float64(angle) * 0.5)
+	s := T(sin)
 	q[0] = axis[0] * s
 	q[1] = axis[1] * s
 	q[2] = axis[2] * s
-	q[3] = Number(c)
+	q[3] = T(c)
 	return q
 }
 
@@ -109,16 +116,16 @@ func (q *Quat) SetFromAxisAngle(axis *Vec3, angle Number) (*Quat) {
  * @param {Vec3} [targetAxis] A vector object to reuse for storing the axis.
  * @return {Vec3, Number} first elemnt is the axis and the second is the angle in radians.
  */
-func (q *Quat) ToAxisAngle(targetAxis *Vec3) (*Vec3, Number) {
+func (q *QuatT[T]) ToAxisAngle(targetAxis *Vec3T[T]) (*Vec3T[T], T) {
 	if targetAxis == nil {
-		targetAxis = &Vec3{}
+		targetAxis = &Vec3T[T]{}
 	}
 
 	q.Normalize() // if w>1 acos and sqrt will produce errors, this cant happen if quaternion is normalised
 
 	w := float64(q[3])
-	angle := Number(2 * math.Acos(w))
-	s := Number(math.Sqrt(1 - w*w)) // assuming quaternion normalised then w is less than 1, so term always positive.
+	angle := T(2 * detAcos(w))
+	s := T(math.Sqrt(1 - float64(w*w))) // assuming quaternion normalised then w is less than 1, so term always positive.
 
 	if (s < 0.001) { // test to avoid divide by zero, s is always positive due to sqrt
 		// if s close to zero then direction of axis not important
@@ -140,22 +147,22 @@ func (q *Quat) ToAxisAngle(targetAxis *Vec3) (*Vec3, Number) {
  * @param {Vec3} u
  * @param {Vec3} v
  */
-func (q *Quat) SetFromVectors(u *Vec3, v *Vec3) (*Quat) {
+func (q *QuatT[T]) SetFromVectors(u *Vec3T[T], v *Vec3T[T]) (*QuatT[T]) {
 	if u.IsAntiparallelTo(v) {
-		t1 := NewVec3()
-		t2 := NewVec3()
+		var t1, t2 Vec3T[T]
 
-		u.Tangents(t1, t2)
-		q.SetFromAxisAngle(t1, math.Pi)
+		u.Tangents(&t1, &t2)
+		q.SetFromAxisAngle(&t1, math.Pi)
 	} else {
-		a := u.Cross(v, nil)
+		var a Vec3T[T]
+		u.Cross(v, &a)
 		q[0] = a[0]
 		q[1] = a[1]
 		q[2] = a[2]
 		//un := u.Norm()
 		//vn := v.Norm()
 		//q[3] = Number(math.Sqrt(float64( un*un * vn*vn ))) + u.Dot(v)
-		q[3] = Number(math.Sqrt(float64( u.LengthSquared() * v.LengthSquared() ))) + u.Dot(v)
+		q[3] = T(math.Sqrt(float64( u.LengthSquared() * v.LengthSquared() ))) + u.Dot(v)
 		q.Normalize()
 	}
 	return q
@@ -168,18 +175,19 @@ func (q *Quat) SetFromVectors(u *Vec3, v *Vec3) (*Quat) {
  * @param {Quaternion} target Optional.
  * @return {Quaternion}
  */
-func (q *Quat) Mult(q1 *Quat, target *Quat) (*Quat) {
+func (q *QuatT[T]) Mult(q1 *QuatT[T], target *QuatT[T]) (*QuatT[T]) {
 	if target == nil {
-		target = NewQuat()
+		target = NewQuatT[T]()
 	}
 
 	ax, ay, az, aw := q[0], q[1], q[2], q[3]
 	bx, by, bz, bw := q1[0], q1[1], q1[2], q1[3]
 
-	target[0] = ax * bw + aw * bx + ay * bz - az * by
-	target[1] = ay * bw + aw * by + az * bx - ax * bz
-	target[2] = az * bw + aw * bz + ax * by - ay * bx
-	target[3] = aw * bw - ax * bx - ay * by - az * bz
+	// every product rounded on its own so it is not fused into FMA
+	target[0] = T(ax * bw) + T(aw * bx) + T(ay * bz) - T(az * by)
+	target[1] = T(ay * bw) + T(aw * by) + T(az * bx) - T(ax * bz)
+	target[2] = T(az * bw) + T(aw * bz) + T(ax * by) - T(ay * bx)
+	target[3] = T(aw * bw) - T(ax * bx) - T(ay * by) - T(az * bz)
 
 	return target
 }
@@ -190,15 +198,15 @@ func (q *Quat) Mult(q1 *Quat, target *Quat) (*Quat) {
  * @param {Quaternion} target
  * @return {Quaternion}
  */
-func (q *Quat) Inverse(target *Quat) (*Quat) {
+func (q *QuatT[T]) Inverse(target *QuatT[T]) (*QuatT[T]) {
 	if target == nil {
-		target = NewQuat()
+		target = NewQuatT[T]()
 	}
 
 	x, y, z, w := q[0], q[1], q[2], q[3]
 
 	q.Conjugate(target)
-	inorm2 := 1 / (x*x + y*y + z*z + w*w)
+	inorm2 := 1 / (T(x*x) + T(y*y) + T(z*z) + T(w*w))
 
 	target[0] *= inorm2
 	target[1] *= inorm2
@@ -214,9 +222,9 @@ func (q *Quat) Inverse(target *Quat) (*Quat) {
  * @param {Quaternion} target
  * @return {Quaternion}
  */
-func (q *Quat) Conjugate(target *Quat) (*Quat) {
+func (q *QuatT[T]) Conjugate(target *QuatT[T]) (*QuatT[T]) {
 	if target == nil {
-		target = &Quat{}
+		target = &QuatT[T]{}
 	}
 
 	target[0] = -q[0]
@@ -231,14 +239,14 @@ func (q *Quat) Conjugate(target *Quat) (*Quat) {
  * Normalize the quaternion. Note that this changes the values of the quaternion.
  * @method normalize
  */
-func (q *Quat) Normalize() (*Quat) {
+func (q *QuatT[T]) Normalize() (*QuatT[T]) {
 	x, y, z, w := q[0], q[1], q[2], q[3]
-	l := math.Sqrt(float64(x*x + y*y + z*z + w*w))
+	l := math.Sqrt(float64(T(x*x) + T(y*y) + T(z*z) + T(w*w)))
 
 	if l == 0 {
 		q[0], q[1], q[2], q[3] = 0, 0, 0, 0
 	} else {
-		s := Number(1 / l)
+		s := T(1 / l)
 		q[0] *= s
 		q[1] *= s
 		q[2] *= s
@@ -253,9 +261,9 @@ func (q *Quat) Normalize() (*Quat) {
  * @see http://jsperf.com/fast-quaternion-normalization
  * @author unphased, https://github.com/unphased
  */
-func (q *Quat) NormalizeFast() (*Quat) {
+func (q *QuatT[T]) NormalizeFast() (*QuatT[T]) {
 	x, y, z, w := q[0], q[1], q[2], q[3]
-	f := (3.0 - (x*x + y*y + z*z + w*w) ) / 2.0
+	f := (3.0 - (T(x*x) + T(y*y) + T(z*z) + T(w*w)) ) / 2.0
 
 	if f == 0 {
 		q[0], q[1], q[2], q[3] = 0, 0, 0, 0
@@ -276,23 +284,23 @@ func (q *Quat) NormalizeFast() (*Quat) {
  * @param {Vec3} target Optional
  * @return {Vec3}
  */
-func (q *Quat) VMult(v *Vec3, target *Vec3) (*Vec3) {
+func (q *QuatT[T]) VMult(v *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = NewVec3()
+		target = NewVec3T[T]()
 	}
 
 	x, y, z := v[0], v[1], v[2]
 	qx, qy, qz, qw := q[0], q[1], q[2], q[3]
 
-	// q*v
-	ix :=  qw * x + qy * z - qz * y
-	iy :=  qw * y + qz * x - qx * z
-	iz :=  qw * z + qx * y - qy * x
-	iw := -qx * x - qy * y - qz * z
+	// q*v, every product rounded on its own so it is not fused into FMA
+	ix :=  T(qw * x) + T(qy * z) - T(qz * y)
+	iy :=  T(qw * y) + T(qz * x) - T(qx * z)
+	iz :=  T(qw * z) + T(qx * y) - T(qy * x)
+	iw := -T(qx * x) - T(qy * y) - T(qz * z)
 
-	target[0] = ix * qw + iw * -qx + iy * -qz - iz * -qy
-	target[1] = iy * qw + iw * -qy + iz * -qx - ix * -qz
-	target[2] = iz * qw + iw * -qz + ix * -qy - iy * -qx
+	target[0] = T(ix * qw) + T(iw * -qx) + T(iy * -qz) - T(iz * -qy)
+	target[1] = T(iy * qw) + T(iw * -qy) + T(iz * -qx) - T(ix * -qz)
+	target[2] = T(iz * qw) + T(iw * -qz) + T(ix * -qy) - T(iy * -qx)
 
 	return target
 }
@@ -306,9 +314,9 @@ func (q *Quat) VMult(v *Vec3, target *Vec3) (*Vec3) {
  *
  * refactor from Three.js
 */
-func (q *Quat) ToEuler(target *Vec3, order AxisOrder) (*Vec3) {
+func (q *QuatT[T]) ToEuler(target *Vec3T[T], order AxisOrder) (*Vec3T[T]) {
 	if target == nil {
-		target = NewVec3()
+		target = NewVec3T[T]()
 	}
 
 	// target[0], x
@@ -318,9 +326,9 @@ func (q *Quat) ToEuler(target *Vec3, order AxisOrder) (*Vec3) {
 	x, y, z, w := q[0], q[1], q[2], q[3]
 
 	x2, y2, z2 := x + x, y + y, z + z
-	xx, xy, xz := x * x2, x * y2, x * z2
-	yy, yz, zz := y * y2, y * z2, z * z2
-	wx, wy, wz := w * x2, w * y2, w * z2
+	xx, xy, xz := T(x * x2), T(x * y2), T(x * z2)
+	yy, yz, zz := T(y * y2), T(y * z2), T(z * z2)
+	wx, wy, wz := T(w * x2), T(w * y2), T(w * z2)
 
 	m11 := 1 - ( yy + zz )
 	m12 := xy - wz
@@ -337,93 +345,93 @@ func (q *Quat) ToEuler(target *Vec3, order AxisOrder) (*Vec3) {
 
 	switch order {
 	case XYZ:
-		target[1] = Number(math.Asin( clampF( m13, -1, 1 ) ))
+		target[1] = T(detAsin( clampF( m13, -1, 1 ) ))
 
 		if math.Abs(float64( m13 )) < 0.99999 {
 
-			target[0] = math.Atan2( float64(-m23), float64(m33) )
-			target[2] = math.Atan2( float64(-m12), float64(m11) )
+			target[0] = T(detAtan2( float64(-m23), float64(m33) ))
+			target[2] = T(detAtan2( float64(-m12), float64(m11) ))
 
 		} else {
 
-			target[0] = math.Atan2( float64(m32), float64(m22) )
+			target[0] = T(detAtan2( float64(m32), float64(m22) ))
 			target[2] = 0;
 
 		}
 
 	case YXZ:
-		target[0] = Number(math.Asin( -clampF( m23, -1, 1 ) ))
+		target[0] = T(detAsin( -clampF( m23, -1, 1 ) ))
 
 		if math.Abs(float64( m23 )) < 0.99999 {
 
-			target[1] = math.Atan2( float64(m13), float64(m33) )
-			target[2] = math.Atan2( float64(m21), float64(m22) )
+			target[1] = T(detAtan2( float64(m13), float64(m33) ))
+			target[2] = T(detAtan2( float64(m21), float64(m22) ))
 
 		} else {
 
-			target[1] = math.Atan2( float64(-m31), float64(m11) )
+			target[1] = T(detAtan2( float64(-m31), float64(m11) ))
 			target[2] = 0
 
 		}
 
 	case ZXY:
-		target[0] = Number(math.Asin( clampF( m32, -1, 1 ) ))
+		target[0] = T(detAsin( clampF( m32, -1, 1 ) ))
 
 		if math.Abs(float64( m32 )) < 0.99999 {
 
-			target[1] = math.Atan2( float64(-m31), float64(m33) )
-			target[2] = math.Atan2( float64(-m12), float64(m22) )
+			target[1] = T(detAtan2( float64(-m31), float64(m33) ))
+			target[2] = T(detAtan2( float64(-m12), float64(m22) ))
 
 		} else {
 
 			target[1] = 0
-			target[2] = math.Atan2( float64(m21), float64(m11) )
+			target[2] = T(detAtan2( float64(m21), float64(m11) ))
 
 		}
 
 	case ZYX:
-		target[1] = Number(math.Asin( -clamp( m31, -1, 1 ) ))
+		target[1] = T(detAsin( -clampF( m31, -1, 1 ) ))
 
 		if math.Abs(float64( m31 )) < 0.99999 {
 
-			target[0] = math.Atan2( float64(m32), float64(m33) )
-			target[2] = math.Atan2( float64(m21), float64(m11) )
+			target[0] = T(detAtan2( float64(m32), float64(m33) ))
+			target[2] = T(detAtan2( float64(m21), float64(m11) ))
 
 		} else {
 
 			target[0] = 0
-			target[2] = math.Atan2( float64(-m12), float64(m22) )
+			target[2] = T(detAtan2( float64(-m12), float64(m22) ))
 
 		}
 
 	default:
 		fallthrough
 	case YZX:
-		target[2] = Number(math.Asin( clamp( m21, -1, 1 ) ))
+		target[2] = T(detAsin( clampF( m21, -1, 1 ) ))
 
 		if math.Abs(float64( m21 )) < 0.99999 {
 
-			target[0] = math.Atan2( float64(-m23), float64(m22) )
-			target[1] = math.Atan2( float64(-m31), float64(m11) )
+			target[0] = T(detAtan2( float64(-m23), float64(m22) ))
+			target[1] = T(detAtan2( float64(-m31), float64(m11) ))
 
 		} else {
 
 			target[0] = 0
-			target[1] = math.Atan2( float64(m13), float64(m33) )
+			target[1] = T(detAtan2( float64(m13), float64(m33) ))
 
 		}
 
 	case XZY:
-		target[2] = Number(math.Asin( -clamp( m12, -1, 1 ) ))
+		target[2] = T(detAsin( -clampF( m12, -1, 1 ) ))
 
 		if math.Abs(float64( m12 )) < 0.99999 {
 
-			target[0] = math.Atan2( float64(m32), float64(m22) )
-			target[1] = math.Atan2( float64(m13), float64(m11) )
+			target[0] = T(detAtan2( float64(m32), float64(m22) ))
+			target[1] = T(detAtan2( float64(m13), float64(m11) ))
 
 		} else {
 
-			target[0] = math.Atan2( float64(-m23), float64(m33) )
+			target[0] = T(detAtan2( float64(-m23), float64(m33) ))
 			target[1] = 0
 
 		}
@@ -441,50 +449,56 @@ func (q *Quat) ToEuler(target *Vec3, order AxisOrder) (*Vec3) {
  * @param {Number} z
  * @param {String} order The order to apply angles: 'XYZ' or 'YXZ' or any other combination
  */
-func (q *Quat) SetFromEuler(x Number,y Number,z Number, order AxisOrder) (*Quat) {
+func (q *QuatT[T]) SetFromEuler(x T,y T,z T, order AxisOrder) (*QuatT[T]) {
+
+	s, c := detSincos(float64( x / 2))
+	c1, s1 := T(c), T(s)
 
-	c, s := math.Sincos(float64( x / 2))
-	c1, s1 := Number(c), Number(s)
+	s, c = detSincos(float64( y / 2))
+	c2, s2 := T(c), T(s)
 
-	c, s = math.Sincos(float64( y / 2))
-	c2, s2 := Number(c), Number(s)
+	s, c = detSincos(float64( z / 2))
+	c3, s3 := T(c), T(s)
 
-	c, s = math.Sincos(float64( z / 2))
-	c3, s3 := Number(c), Number(s)
+	// the eight products, each rounded on its own so the sums are not fused into FMA
+	scc, css := T(T(s1 * c2) * c3), T(T(c1 * s2) * s3)
+	csc, scs := T(T(c1 * s2) * c3), T(T(s1 * c2) * s3)
+	ccs, ssc := T(T(c1 * c2) * s3), T(T(s1 * s2) * c3)
+	ccc, sss := T(T(c1 * c2) * c3), T(T(s1 * s2) * s3)
 
 	switch order {
 	default:
 		fallthrough
 	case XYZ:
-		q[0] = s1 * c2 * c3 + c1 * s2 * s3
-		q[1] = c1 * s2 * c3 - s1 * c2 * s3
-		q[2] = c1 * c2 * s3 + s1 * s2 * c3
-		q[3] = c1 * c2 * c3 - s1 * s2 * s3
+		q[0] = scc + css
+		q[1] = csc - scs
+		q[2] = ccs + ssc
+		q[3] = ccc - sss
 	case YXZ:
-		q[0] = s1 * c2 * c3 + c1 * s2 * s3
-		q[1] = c1 * s2 * c3 - s1 * c2 * s3
-		q[2] = c1 * c2 * s3 - s1 * s2 * c3
-		q[3] = c1 * c2 * c3 + s1 * s2 * s3
+		q[0] = scc + css
+		q[1] = csc - scs
+		q[2] = ccs - ssc
+		q[3] = ccc + sss
 	case ZXY:
-		q[0] = s1 * c2 * c3 - c1 * s2 * s3
-		q[1] = c1 * s2 * c3 + s1 * c2 * s3
-		q[2] = c1 * c2 * s3 + s1 * s2 * c3
-		q[3] = c1 * c2 * c3 - s1 * s2 * s3
+		q[0] = scc - css
+		q[1] = csc + scs
+		q[2] = ccs + ssc
+		q[3] = ccc - sss
 	case ZYX:
-		q[0] = s1 * c2 * c3 - c1 * s2 * s3
-		q[1] = c1 * s2 * c3 + s1 * c2 * s3
-		q[2] = c1 * c2 * s3 - s1 * s2 * c3
-		q[3] = c1 * c2 * c3 + s1 * s2 * s3
+		q[0] = scc - css
+		q[1] = csc + scs
+		q[2] = ccs - ssc
+		q[3] = ccc + sss
 	case YZX:
-		q[0] = s1 * c2 * c3 + c1 * s2 * s3
-		q[1] = c1 * s2 * c3 + s1 * c2 * s3
-		q[2] = c1 * c2 * s3 - s1 * s2 * c3
-		q[3] = c1 * c2 * c3 - s1 * s2 * s3
+		q[0] = scc + css
+		q[1] = csc + scs
+		q[2] = ccs - ssc
+		q[3] = ccc - sss
 	case XZY:
-		q[0] = s1 * c2 * c3 - c1 * s2 * s3
-		q[1] = c1 * s2 * c3 - s1 * c2 * s3
-		q[2] = c1 * c2 * s3 + s1 * s2 * c3
-		q[3] = c1 * c2 * c3 + s1 * s2 * s3
+		q[0] = scc - css
+		q[1] = csc - scs
+		q[2] = ccs + ssc
+		q[3] = ccc + sss
 	}
 
 	return q
@@ -500,16 +514,16 @@ func (q *Quat) SetFromEuler(x Number,y Number,z Number, order AxisOrder) (*Quat)
  * @param {Quaternion} [target] A quaternion to store the result in. If not provided, a new one will be created.
  * @returns {Quaternion} The "target" object
  */
-func (q *Quat) Slerp(toQuat *Quat, t Number, target *Quat) (*Quat) {
+func (q *QuatT[T]) Slerp(toQuat *QuatT[T], t T, target *QuatT[T]) (*QuatT[T]) {
 	if target == nil {
-		target = &Quat{}
+		target = &QuatT[T]{}
 	}
 
 	ax, ay, az, aw := q[0], q[1], q[2], q[3]
 	bx, by, bz, bw := toQuat[0], toQuat[1], toQuat[2], toQuat[3]
 
 	// calc cosine
-	cosom := ax * bx + ay * by + az * bz + aw * bw
+	cosom := T(ax * bx) + T(ay * by) + T(az * bz) + T(aw * bw)
 
 	// adjust signs (if necessary)
 	if ( cosom < 0.0 ) {
@@ -520,20 +534,20 @@ func (q *Quat) Slerp(toQuat *Quat, t Number, target *Quat) (*Quat) {
 		bw = - bw
 	}
 
-	var scale0 Number
-	var scale1 Number
+	var scale0 T
+	var scale1 T
 
 	// calculate coefficients
 	if ( (1.0 - cosom) > 0.000001 ) {
 		// standard case (slerp)
-		omega  := math.Acos(float64(cosom))
-		sinom  := Number(math.Sin(float64(omega)))
+		omega  := detAcos(float64(cosom))
+		sinom  := T(detSin(omega))
 
-		t0 := math.Sin(float64(1.0 - t) * omega)
-		scale0 = Number(t0) / sinom
+		t0 := detSin(float64(float64(1.0 - t) * omega))
+		scale0 = T(t0) / sinom
 
-		t0 = math.Sin(float64(t) * omega)
-		scale1 = Number(t0) / sinom
+		t0 = detSin(float64(float64(t) * omega))
+		scale1 = T(t0) / sinom
 	} else {
 		// "from" and "to" quaternions are very close
 		//  ... so we can do a linear interpolation
@@ -542,10 +556,10 @@ func (q *Quat) Slerp(toQuat *Quat, t Number, target *Quat) (*Quat) {
 	}
 
 	// calculate final values
-	target[0] = scale0 * ax + scale1 * bx
-	target[1] = scale0 * ay + scale1 * by
-	target[2] = scale0 * az + scale1 * bz
-	target[3] = scale0 * aw + scale1 * bw
+	target[0] = T(scale0 * ax) + T(scale1 * bx)
+	target[1] = T(scale0 * ay) + T(scale1 * by)
+	target[2] = T(scale0 * az) + T(scale1 * bz)
+	target[3] = T(scale0 * aw) + T(scale1 * bw)
 
 	return target
 }
@@ -559,23 +573,23 @@ func (q *Quat) Slerp(toQuat *Quat, t Number, target *Quat) (*Quat) {
  * @param  {Quaternion} target
  * @return {Quaternion} The "target" object
  */
-func (q *Quat) Integrate(angularVelocity *Vec3, dt Number, angularFactor *Vec3, target *Quat) (*Quat) {
+func (q *QuatT[T]) Integrate(angularVelocity *Vec3T[T], dt T, angularFactor *Vec3T[T], target *QuatT[T]) (*QuatT[T]) {
 	if target == nil {
-		target = NewQuat()
+		target = NewQuatT[T]()
 	}
 
-	ax := angularVelocity[0] * angularFactor[0]
-	ay := angularVelocity[1] * angularFactor[1]
-	az := angularVelocity[2] * angularFactor[2]
+	ax := T(angularVelocity[0] * angularFactor[0])
+	ay := T(angularVelocity[1] * angularFactor[1])
+	az := T(angularVelocity[2] * angularFactor[2])
 
 	bx, by, bz, bw := q[0], q[1], q[2], q[3]
 
 	half_dt := dt * 0.5
 
-	target[0] += half_dt * (ax * bw + ay * bz - az * by)
-	target[1] += half_dt * (ay * bw + az * bx - ax * bz)
-	target[2] += half_dt * (az * bw + ax * by - ay * bx)
-	target[3] += half_dt * (- ax * bx - ay * by - az * bz)
+	target[0] += T(half_dt * (T(ax * bw) + T(ay * bz) - T(az * by)))
+	target[1] += T(half_dt * (T(ay * bw) + T(az * bx) - T(ax * bz)))
+	target[2] += T(half_dt * (T(az * bw) + T(ax * by) - T(ay * bx)))
+	target[3] += T(half_dt * (- T(ax * bx) - T(ay * by) - T(az * bz)))
 
 	return target
 }
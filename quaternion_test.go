@@ -3,6 +3,7 @@ package physics
 import (
 	"testing"
 	"math"
+	"math/rand"
 )
 
 func TestQuatEquals(t *testing.T) {
@@ -57,7 +58,7 @@ func TestQuatInverse(t *testing.T) {
 	var q = NewQuat().Set(1, 2, 3, 4)
 	q = q.Inverse(nil)
 
-	if !q.IsEquals(qok) {
+	if !q.AlmostEquals(qok) {
 		t.Error("Error Calculating Inverse, got ", q, qok)
 	}
 
@@ -65,7 +66,7 @@ func TestQuatInverse(t *testing.T) {
 	q.Set(1, 2, 3, 4)
 	q.Inverse(q)
 
-	if !q.IsEquals(qok) {
+	if !q.AlmostEquals(qok) {
 		t.Error("Error Calculating Inverse, got ", q, qok)
 	}
 
@@ -88,7 +89,7 @@ func TestQuatSlerp(t *testing.T) {
 
 	qa.Slerp(qb, 0.5, qb)
 
-	if !qb.IsEquals(qok) {
+	if !qb.AlmostEquals(qok) {
 		t.Error("Error Calculating Slerp, got ", qb, qok, qa)
 	}
 
@@ -111,7 +112,7 @@ func TestQuatSlerp2(t *testing.T) {
 
 	qb = qa.Slerp(qb, 0.5, nil)
 
-	if !qb.IsEquals(qok) {
+	if !qb.AlmostEquals(qok) {
 		t.Error("Error Calculating Slerp, got ", qb, qok, qa)
 	}
 
@@ -159,9 +160,123 @@ func TestQuatToEuler(t *testing.T) {
 
 	// we should expect (0,0,pi/4)
 	vok := NewVec3().Set(0, 0, math.Pi / 4)
-	if !vok.IsEquals(euler) {
+	if !vok.AlmostEquals(euler) {
 		t.Error("Error Calculating ToEuler, got ", euler, vok, q)
 	}
 }
 
 
+var benchQuat Quat
+
+func benchQuats() (*Quat, *Quat) {
+	q := NewQuat().SetFromAxisAngle(NewVec3().Set(1, 2, 3).Unit(nil), 0.7)
+	r := NewQuat().SetFromAxisAngle(NewVec3().Set(-1, 0, 2).Unit(nil), 1.9)
+	return q, r
+}
+
+func BenchmarkQuatNormalize(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchQuat.Set(1, 2, 3, 4)
+		benchQuat.Normalize()
+	}
+}
+
+func BenchmarkQuatMult(b *testing.B) {
+	b.ReportAllocs()
+	q, r := benchQuats()
+	for i := 0; i < b.N; i++ {
+		q.Mult(r, &benchQuat)
+	}
+}
+
+func BenchmarkQuatVMult(b *testing.B) {
+	b.ReportAllocs()
+	q, _ := benchQuats()
+	v := NewVec3().Set(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		q.VMult(v, &benchVec3)
+	}
+}
+
+func BenchmarkQuatSlerp(b *testing.B) {
+	b.ReportAllocs()
+	q, r := benchQuats()
+	for i := 0; i < b.N; i++ {
+		q.Slerp(r, 0.3, &benchQuat)
+	}
+}
+
+func BenchmarkQuatIntegrate(b *testing.B) {
+	b.ReportAllocs()
+	q, _ := benchQuats()
+	w, f := NewVec3().Set(0.1, 2, -1), NewVec3().Set(1, 1, 1)
+	for i := 0; i < b.N; i++ {
+		q.Integrate(w, 1.0 / 60, f, &benchQuat)
+	}
+}
+
+func BenchmarkQuatToEuler(b *testing.B) {
+	b.ReportAllocs()
+	q, _ := benchQuats()
+	for i := 0; i < b.N; i++ {
+		q.ToEuler(&benchVec3, YZX)
+	}
+}
+
+func BenchmarkQuatSetFromVectors(b *testing.B) {
+	b.ReportAllocs()
+	u, v := NewVec3().Set(1, 0, 0), NewVec3().Set(0, 1, 1).Unit(nil)
+	for i := 0; i < b.N; i++ {
+		benchQuat.SetFromVectors(u, v)
+	}
+}
+
+func BenchmarkTransformPointToLocal(b *testing.B) {
+	b.ReportAllocs()
+	q, _ := benchQuats()
+	tf := Transform{ Pos: NewVec3().Set(1, 2, 3), Rot: q }
+	p := NewVec3().Set(4, 5, 6)
+	for i := 0; i < b.N; i++ {
+		tf.PointToLocal(p, &benchVec3)
+	}
+}
+
+
+// Hash of the exact bits of the quaternion math, recorded on amd64. The same bits on arm64
+// show that no FMA or architecture specific math.Sin/Acos slipped in, see Determinism in the README.
+func quatBitsHash() (uint64) {
+	h := NewStateHash()
+	r := rand.New(rand.NewSource(7))
+	num := func() (Number) {
+		return Number(r.Float64() * 4 - 2)
+	}
+	var q, p, s Quat
+	var v Vec3
+	for i := 0; i < 200; i++ {
+		v.Set(num(), num(), num())
+		q.SetFromAxisAngle(v.Unit(nil), num() * 3)
+		p.SetFromEuler(num(), num(), num(), AxisOrder(i % 6))
+		q.Slerp(&p, Number(r.Float64()), &s)
+		h.Quat(&q).Quat(&p).Quat(&s)
+
+		s.Set(num(), num(), num(), num()).Normalize()
+		h.Quat(&s).Quat(q.Mult(&p, nil)).Quat(q.Integrate(&v, 0.01, NewVec3().Set(1, 1, 1), p.Clone()))
+		h.Vec3(q.VMult(&v, nil)).Vec3(q.ToEuler(nil, AxisOrder(i % 6)))
+		axis, angle := s.ToAxisAngle(nil)
+		h.Vec3(axis).Number(angle)
+	}
+	return uint64(*h)
+}
+
+func TestQuatBits(t *testing.T) {
+
+	want := uint64(10669045876340451912)
+	if bitSizeOf[Number]() == 32 {
+		want = 7104413811669973188
+	}
+	if got := quatBitsHash(); got != want {
+		t.Error("quaternion math gives different bits, got ", got, " want ", want)
+	}
+
+}
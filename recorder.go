@@ -0,0 +1,409 @@
+package physics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+type TrajectoryFormat int
+
+const (
+	TrajectoryCSV TrajectoryFormat = iota // 0
+	TrajectoryJSONL // 1
+)
+
+var (
+	ErrTrajectoryHeader = errors.New("physics: trajectory csv header does not match")
+	ErrTrajectoryID = errors.New("physics: trajectory sample id out of range")
+)
+
+var trajectoryHeader = []string{
+	"step", "time", "id",
+	"px", "py", "pz",
+	"qx", "qy", "qz", "qw",
+	"vx", "vy", "vz",
+	"wx", "wy", "wz",
+}
+
+/**
+ * State of one object at one step.
+ * @class TrajectorySample
+ */
+type TrajectorySample struct {
+	Step int `json:"step"`
+	Time Number `json:"time"`
+	ID int `json:"id"`
+	Position Vec3 `json:"position"`
+	Quaternion Quat `json:"quaternion"`
+	Velocity Vec3 `json:"velocity"`
+	AngularVelocity Vec3 `json:"angularVelocity"`
+}
+
+/**
+ * Something a Recorder can sample and a Trajectory can be replayed into.
+ * @class Recordable
+ */
+type Recordable interface {
+	// AppendSamples appends one sample per object, Step is filled in by the Recorder.
+	AppendSamples(samples []TrajectorySample) ([]TrajectorySample)
+	// ApplySamples sets the objects to the recorded state.
+	ApplySamples(samples []TrajectorySample) (error)
+}
+
+/**
+ * One sample per particle, ID is the particle index. Particles have no orientation.
+ * @method appendSamples
+ */
+func (sb *SoftBody) AppendSamples(samples []TrajectorySample) ([]TrajectorySample) {
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		samples = append(samples, TrajectorySample{
+			Time: sb.Time,
+			ID: i,
+			Position: p.Position,
+			Quaternion: Quat{ 0, 0, 0, 1 },
+			Velocity: p.Velocity,
+		})
+	}
+	return samples
+}
+
+/**
+ * @method applySamples
+ */
+func (sb *SoftBody) ApplySamples(samples []TrajectorySample) (error) {
+	for i := range samples {
+		if samples[i].ID < 0 || samples[i].ID >= len(sb.Particles) {
+			return ErrTrajectoryID
+		}
+	}
+	for i := range samples {
+		s := &samples[i]
+		p := &sb.Particles[s.ID]
+		p.Position = s.Position
+		p.Velocity = s.Velocity
+		sb.Time = s.Time
+	}
+	return nil
+}
+
+/**
+ * Writes the state of a Recordable every step, as CSV with a header line or as JSON Lines.
+ * @class Recorder
+ */
+type Recorder struct {
+	format TrajectoryFormat
+	w *bufio.Writer
+	csv *csv.Writer
+	step int
+	samples []TrajectorySample
+	record []string
+	buf []byte
+}
+
+func NewRecorder(w io.Writer, format TrajectoryFormat) (*Recorder) {
+	r := &Recorder{ format: format, w: bufio.NewWriter(w) }
+	if format == TrajectoryCSV {
+		r.csv = csv.NewWriter(r.w)
+		r.record = make([]string, len(trajectoryHeader))
+	}
+	return r
+}
+
+/**
+ * Sample and write the current state, call once per step.
+ * @method record
+ * @param {Recordable} src
+ */
+func (r *Recorder) Record(src Recordable) (error) {
+	if r.format == TrajectoryCSV && r.step == 0 {
+		if err := r.csv.Write(trajectoryHeader); err != nil {
+			return err
+		}
+	}
+
+	r.samples = src.AppendSamples(r.samples[:0])
+	for i := range r.samples {
+		s := &r.samples[i]
+		s.Step = r.step
+		if err := r.write(s); err != nil {
+			return err
+		}
+	}
+	r.step++
+	return nil
+}
+
+func (r *Recorder) write(s *TrajectorySample) (error) {
+	if r.format == TrajectoryJSONL {
+		r.buf = appendTrajectoryJSON(r.buf[:0], s)
+		r.buf = append(r.buf, '\n')
+		_, err := r.w.Write(r.buf)
+		return err
+	}
+
+	bits := bitSizeOf[Number]()
+	f := func(n Number) (string) {
+		return strconv.FormatFloat(float64(n), 'g', -1, bits)
+	}
+	rec := r.record
+	rec[0], rec[1], rec[2] = strconv.Itoa(s.Step), f(s.Time), strconv.Itoa(s.ID)
+	for k := 0; k < 3; k++ {
+		rec[3 + k] = f(s.Position[k])
+		rec[10 + k] = f(s.Velocity[k])
+		rec[13 + k] = f(s.AngularVelocity[k])
+	}
+	for k := 0; k < 4; k++ {
+		rec[6 + k] = f(s.Quaternion[k])
+	}
+	return r.csv.Write(rec)
+}
+
+// The same fields json.Marshal writes, but NaN and Inf are written as the strings "NaN", "+Inf" and "-Inf"
+// like in CSV, json.Marshal would fail on them and a run that blows up is worth recording.
+func appendTrajectoryJSON(b []byte, s *TrajectorySample) ([]byte) {
+	bits := bitSizeOf[Number]()
+	num := func(b []byte, n Number) ([]byte) {
+		f := float64(n)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return strconv.AppendQuote(b, strconv.FormatFloat(f, 'g', -1, bits))
+		}
+		return strconv.AppendFloat(b, f, 'g', -1, bits)
+	}
+	vec := func(b []byte, name string, keys string, ns ...Number) ([]byte) {
+		b = append(b, `,"`...)
+		b = append(b, name...)
+		b = append(b, `":{`...)
+		for i, n := range ns {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, '"', keys[i], '"', ':')
+			b = num(b, n)
+		}
+		return append(b, '}')
+	}
+
+	b = append(b, `{"step":`...)
+	b = strconv.AppendInt(b, int64(s.Step), 10)
+	b = append(b, `,"time":`...)
+	b = num(b, s.Time)
+	b = append(b, `,"id":`...)
+	b = strconv.AppendInt(b, int64(s.ID), 10)
+	b = vec(b, "position", "xyz", s.Position[:]...)
+	b = vec(b, "quaternion", "xyzw", s.Quaternion[:]...)
+	b = vec(b, "velocity", "xyz", s.Velocity[:]...)
+	b = vec(b, "angularVelocity", "xyz", s.AngularVelocity[:]...)
+	return append(b, '}')
+}
+
+// a JSON number, or one of the strings written by appendTrajectoryJSON
+type trajectoryNumber float64
+
+func (n *trajectoryNumber) UnmarshalJSON(data []byte) (error) {
+	var f float64
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		f = v
+	} else if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*n = trajectoryNumber(f)
+	return nil
+}
+
+type trajectoryVec3 Vec3
+
+func (v *trajectoryVec3) UnmarshalJSON(data []byte) (error) {
+	return parseJSONAs[Number, trajectoryNumber](data, "xyz", v[:])
+}
+
+type trajectoryQuat Quat
+
+func (q *trajectoryQuat) UnmarshalJSON(data []byte) (error) {
+	return parseJSONAs[Number, trajectoryNumber](data, "xyzw", q[:])
+}
+
+// TrajectorySample with fields that accept the non-finite strings
+type trajectorySampleJSON struct {
+	Step int `json:"step"`
+	Time trajectoryNumber `json:"time"`
+	ID int `json:"id"`
+	Position trajectoryVec3 `json:"position"`
+	Quaternion trajectoryQuat `json:"quaternion"`
+	Velocity trajectoryVec3 `json:"velocity"`
+	AngularVelocity trajectoryVec3 `json:"angularVelocity"`
+}
+
+/**
+ * Write buffered output, call when done recording.
+ * @method flush
+ */
+func (r *Recorder) Flush() (error) {
+	if r.csv != nil {
+		r.csv.Flush()
+		if err := r.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return r.w.Flush()
+}
+
+
+/**
+ * Samples of one step.
+ * @class TrajectoryFrame
+ */
+type TrajectoryFrame struct {
+	Step int
+	Time Number
+	Samples []TrajectorySample
+}
+
+/**
+ * A recorded run, in step order.
+ * @class Trajectory
+ */
+type Trajectory struct {
+	Frames []TrajectoryFrame
+}
+
+func (tr *Trajectory) add(s TrajectorySample) {
+	n := len(tr.Frames)
+	if n == 0 || tr.Frames[n - 1].Step != s.Step {
+		tr.Frames = append(tr.Frames, TrajectoryFrame{ Step: s.Step, Time: s.Time })
+		n++
+	}
+	tr.Frames[n - 1].Samples = append(tr.Frames[n - 1].Samples, s)
+}
+
+/**
+ * Set dst to the state of a frame.
+ * @method replay
+ * @param {int} frame
+ * @param {Recordable} dst
+ */
+func (tr *Trajectory) Replay(frame int, dst Recordable) (error) {
+	if frame < 0 || frame >= len(tr.Frames) {
+		return fmt.Errorf("physics: trajectory has no frame %d", frame)
+	}
+	return dst.ApplySamples(tr.Frames[frame].Samples)
+}
+
+/**
+ * Read a trajectory written by a Recorder, the format is detected from the content.
+ * @method readTrajectory
+ * @param {io.Reader} r
+ * @return {Trajectory}
+ */
+func ReadTrajectory(r io.Reader) (*Trajectory, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if err == io.EOF {
+		return &Trajectory{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] == '{' {
+		return readTrajectoryJSONL(br)
+	}
+	return readTrajectoryCSV(br)
+}
+
+func readTrajectoryJSONL(r *bufio.Reader) (*Trajectory, error) {
+	tr := &Trajectory{}
+	line := 0
+	for {
+		data, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			line++
+			var s trajectorySampleJSON
+			if jerr := json.Unmarshal(data, &s); jerr != nil {
+				return nil, fmt.Errorf("physics: trajectory line %d: %v", line, jerr)
+			}
+			tr.add(TrajectorySample{
+				Step: s.Step,
+				Time: Number(s.Time),
+				ID: s.ID,
+				Position: Vec3(s.Position),
+				Quaternion: Quat(s.Quaternion),
+				Velocity: Vec3(s.Velocity),
+				AngularVelocity: Vec3(s.AngularVelocity),
+			})
+		}
+		if err == io.EOF {
+			return tr, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func readTrajectoryCSV(r io.Reader) (*Trajectory, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(trajectoryHeader)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, h := range header {
+		if h != trajectoryHeader[i] {
+			return nil, ErrTrajectoryHeader
+		}
+	}
+
+	tr := &Trajectory{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return tr, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var s TrajectorySample
+		var perr error
+		f := func(i int) (Number) {
+			n, err := strconv.ParseFloat(rec[i], bitSizeOf[Number]())
+			if err != nil && perr == nil {
+				perr = err
+			}
+			return Number(n)
+		}
+		s.Step, perr = strconv.Atoi(rec[0])
+		if perr == nil {
+			s.ID, perr = strconv.Atoi(rec[2])
+		}
+		s.Time = f(1)
+		s.Position.Set(f(3), f(4), f(5))
+		s.Quaternion.Set(f(6), f(7), f(8), f(9))
+		s.Velocity.Set(f(10), f(11), f(12))
+		s.AngularVelocity.Set(f(13), f(14), f(15))
+		if perr != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("physics: trajectory line %d: %v", line, perr)
+		}
+		tr.add(s)
+	}
+}
+
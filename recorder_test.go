@@ -0,0 +1,106 @@
+package physics
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testRecordRope(t *testing.T, format TrajectoryFormat) {
+
+	rope := NewRope(NewVec3().Set(0, 5, 0), NewVec3().Set(2, 5, 0), 4, 1, 1, true, false)
+	rope.Gravity.Set(0, -9.82, 0)
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf, format)
+	var hashes []uint64
+	for i := 0; i < 10; i++ {
+		rope.Step(1.0 / 60)
+		if err := rec.Record(rope); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, rope.StateHash(NewStateHash()).Sum64())
+	}
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := ReadTrajectory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Frames) != 10 || len(tr.Frames[3].Samples) != 5 || tr.Frames[3].Step != 3 {
+		t.Fatal("Error reading trajectory frames, got ", len(tr.Frames))
+	}
+
+	replay := NewRope(NewVec3().Set(0, 5, 0), NewVec3().Set(2, 5, 0), 4, 1, 1, true, false)
+	for i := range tr.Frames {
+		if err := tr.Replay(i, replay); err != nil {
+			t.Fatal(err)
+		}
+		if h := replay.StateHash(NewStateHash()).Sum64(); h != hashes[i] {
+			t.Error("replayed frame differs from the recorded state, frame ", i)
+		}
+	}
+
+}
+
+func TestRecorderCSV(t *testing.T) {
+	testRecordRope(t, TrajectoryCSV)
+}
+
+func TestRecorderJSONL(t *testing.T) {
+	testRecordRope(t, TrajectoryJSONL)
+}
+
+func TestReadTrajectoryErrors(t *testing.T) {
+
+	if _, err := ReadTrajectory(strings.NewReader("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p\n")); err != ErrTrajectoryHeader {
+		t.Error("expected ErrTrajectoryHeader, got ", err)
+	}
+
+	tr, _ := ReadTrajectory(strings.NewReader(`{"step":0,"id":7}` + "\n"))
+	if err := tr.Replay(0, NewSoftBody()); err != ErrTrajectoryID {
+		t.Error("expected ErrTrajectoryID, got ", err)
+	}
+
+}
+
+
+func TestRecorderNonFinite(t *testing.T) {
+
+	for _, format := range []TrajectoryFormat{ TrajectoryCSV, TrajectoryJSONL } {
+		rope := NewRope(NewVec3().Set(0, 5, 0), NewVec3().Set(2, 5, 0), 4, 1, 1, true, false)
+		nan := Number(math.NaN())
+		rope.Particles[2].Position.Set(nan, 1, Number(math.Inf(1)))
+		rope.Particles[3].Velocity.Set(0, Number(math.Inf(-1)), 0)
+
+		var buf bytes.Buffer
+		rec := NewRecorder(&buf, format)
+		if err := rec.Record(rope); err != nil {
+			t.Fatal("format ", format, " should record NaN and Inf, got ", err)
+		}
+		if err := rec.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		tr, err := ReadTrajectory(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := tr.Frames[0].Samples
+		if p := s[2].Position; !math.IsNaN(float64(p[0])) || p[1] != 1 || !math.IsInf(float64(p[2]), 1) || !math.IsInf(float64(s[3].Velocity[1]), -1) {
+			t.Error("Error reading back NaN and Inf, format ", format, ", got ", s[2].Position, s[3].Velocity)
+		}
+	}
+
+	// finite samples are written exactly like json.Marshal
+	sample := TrajectorySample{ Step: 3, Time: 0.25, ID: 1, Position: Vec3{ 1, -2.5, 3 }, Quaternion: Quat{ 0, 0, 0, 1 }, Velocity: Vec3{ 0.1, 0, 0 } }
+	want, _ := json.Marshal(&sample)
+	if got := appendTrajectoryJSON(nil, &sample); string(got) != string(want) {
+		t.Error("Error encoding sample, got ", string(got), " want ", string(want))
+	}
+
+}
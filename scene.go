@@ -0,0 +1,455 @@
+package physics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	ErrSceneIndex = errors.New("physics: scene particle index out of range")
+)
+
+/**
+ * JSON scene description of a SoftBody, read by ReadScene and built with Build.
+ * Cloths and Ropes are expanded into Particles and constraints, a scene written from a live SoftBody only has the expanded form.
+ * @class Scene
+ */
+type Scene struct {
+	Gravity Vec3 `json:"gravity"`
+	Damping *Number `json:"damping,omitempty"`
+	Radius Number `json:"radius,omitempty"`
+	Iterations *int `json:"iterations,omitempty"`
+	Time Number `json:"time,omitempty"`
+
+	Particles []SceneParticle `json:"particles,omitempty"`
+	Distances []SceneDistance `json:"distances,omitempty"`
+	Bendings []SceneBending `json:"bendings,omitempty"`
+	Volumes []SceneVolume `json:"volumes,omitempty"`
+	Cloths []SceneCloth `json:"cloths,omitempty"`
+	Ropes []SceneRope `json:"ropes,omitempty"`
+	Colliders []SceneCollider `json:"colliders,omitempty"`
+	Fields []SceneField `json:"fields,omitempty"`
+}
+
+type SceneParticle struct {
+	Position Vec3 `json:"position"`
+	Velocity Vec3 `json:"velocity"`
+	Mass Number `json:"mass"` // zero for a static particle
+	InvMass *Number `json:"invMass,omitempty"` // overrides Mass when set, written so masses round trip exactly
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// Rest values are computed from the particle positions when omitted.
+type SceneDistance struct {
+	A int `json:"a"`
+	B int `json:"b"`
+	Stiffness Number `json:"stiffness"`
+	RestLength *Number `json:"restLength,omitempty"`
+}
+
+type SceneBending struct {
+	A int `json:"a"`
+	V int `json:"v"`
+	B int `json:"b"`
+	Stiffness Number `json:"stiffness"`
+	RestHeight *Number `json:"restHeight,omitempty"`
+}
+
+type SceneVolume struct {
+	Triangles [][3]int `json:"triangles"`
+	Stiffness Number `json:"stiffness"`
+	Pressure *Number `json:"pressure,omitempty"`
+	RestVolume *Number `json:"restVolume,omitempty"`
+}
+
+type SceneCloth struct {
+	Origin Vec3 `json:"origin"`
+	U Vec3 `json:"u"`
+	V Vec3 `json:"v"`
+	Cols int `json:"cols"`
+	Rows int `json:"rows"`
+	Mass Number `json:"mass"`
+	Stiffness Number `json:"stiffness"`
+	Pinned []int `json:"pinned,omitempty"` // indexes in the cloth, row major
+}
+
+type SceneRope struct {
+	From Vec3 `json:"from"`
+	To Vec3 `json:"to"`
+	Segments int `json:"segments"`
+	Mass Number `json:"mass"`
+	Stiffness Number `json:"stiffness"`
+	Bending Number `json:"bending,omitempty"`
+	AttachFrom bool `json:"attachFrom,omitempty"`
+	AttachTo bool `json:"attachTo,omitempty"`
+}
+
+/**
+ * Position and rotation, the rotation is either a quaternion or euler angles.
+ * @class SceneTransform
+ */
+type SceneTransform struct {
+	Position Vec3 `json:"position"`
+	Quaternion *Quat `json:"quaternion,omitempty"`
+	Euler *SceneEuler `json:"euler,omitempty"`
+}
+
+type SceneEuler struct {
+	X Number `json:"x"`
+	Y Number `json:"y"`
+	Z Number `json:"z"`
+	Order AxisOrder `json:"order"`
+}
+
+func (st *SceneTransform) transform() (Transform) {
+	tf := Transform{ Pos: st.Position.Clone(), Rot: NewQuat() }
+	if st.Quaternion != nil {
+		tf.Rot.Copy(st.Quaternion)
+	} else if st.Euler != nil {
+		tf.Rot.SetFromEuler(st.Euler.X, st.Euler.Y, st.Euler.Z, st.Euler.Order)
+	}
+	return tf
+}
+
+/**
+ * One of "plane", "sphere" or "box", only the fields of that type are used.
+ * @class SceneCollider
+ */
+type SceneCollider struct {
+	Type string `json:"type"`
+
+	Normal *Vec3 `json:"normal,omitempty"` // plane
+	Constant Number `json:"constant,omitempty"` // plane
+
+	Center *Vec3 `json:"center,omitempty"` // sphere
+	Radius Number `json:"radius,omitempty"` // sphere
+
+	Transform *SceneTransform `json:"transform,omitempty"` // box
+	HalfExtents *Vec3 `json:"halfExtents,omitempty"` // box
+}
+
+/**
+ * One of "wind", "pointGravity", "vortex" or "water", only the fields of that type are used.
+ * @class SceneField
+ */
+type SceneField struct {
+	Type string `json:"type"`
+
+	Velocity *Vec3 `json:"velocity,omitempty"` // wind
+	Drag Number `json:"drag,omitempty"` // wind, water
+	Turbulence Number `json:"turbulence,omitempty"` // wind
+	Frequency Number `json:"frequency,omitempty"` // wind
+
+	Center *Vec3 `json:"center,omitempty"` // pointGravity, vortex
+	Strength Number `json:"strength,omitempty"` // pointGravity, vortex
+	MinDistance Number `json:"minDistance,omitempty"` // pointGravity
+	Radius Number `json:"radius,omitempty"` // pointGravity, vortex, zero for unlimited; water, sphere radius
+
+	Axis *Vec3 `json:"axis,omitempty"` // vortex
+	Pull Number `json:"pull,omitempty"` // vortex
+	Lift Number `json:"lift,omitempty"` // vortex
+
+	Height Number `json:"height,omitempty"` // water
+	Density Number `json:"density,omitempty"` // water
+	Gravity Number `json:"gravity,omitempty"` // water
+}
+
+func orZero(v *Vec3) (*Vec3) {
+	if v == nil {
+		return &Vec3{}
+	}
+	return v
+}
+
+func (sc *SceneCollider) collider() (ParticleCollider, error) {
+	switch sc.Type {
+	case "plane":
+		return &PlaneCollider{ Normal: *orZero(sc.Normal), Constant: sc.Constant }, nil
+	case "sphere":
+		return &SphereCollider{ Center: *orZero(sc.Center), Radius: sc.Radius }, nil
+	case "box":
+		c := &BoxCollider{ HalfExtents: *orZero(sc.HalfExtents) }
+		if sc.Transform != nil {
+			c.Transform = sc.Transform.transform()
+		} else {
+			c.Transform = Transform{ Pos: NewVec3(), Rot: NewQuat() }
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("physics: unknown scene collider type %q", sc.Type)
+}
+
+func (sf *SceneField) field() (ForceField, error) {
+	switch sf.Type {
+	case "wind":
+		return &Wind{ Velocity: *orZero(sf.Velocity), Drag: sf.Drag, Turbulence: sf.Turbulence, Frequency: sf.Frequency }, nil
+	case "pointGravity":
+		return &PointGravity{ Center: *orZero(sf.Center), Strength: sf.Strength, MinDistance: sf.MinDistance, Radius: sf.Radius }, nil
+	case "vortex":
+		return &Vortex{ Center: *orZero(sf.Center), Axis: *orZero(sf.Axis), Strength: sf.Strength, Pull: sf.Pull, Lift: sf.Lift, Radius: sf.Radius }, nil
+	case "water":
+		return &Water{ Height: sf.Height, Density: sf.Density, Gravity: sf.Gravity, Drag: sf.Drag, Radius: sf.Radius }, nil
+	}
+	return nil, fmt.Errorf("physics: unknown scene field type %q", sf.Type)
+}
+
+/**
+ * Instantiate the scene.
+ * @method build
+ * @return {SoftBody}
+ */
+func (s *Scene) Build() (*SoftBody, error) {
+	sb := NewSoftBody()
+	sb.Gravity = s.Gravity
+	if s.Damping != nil {
+		sb.Damping = *s.Damping
+	}
+	sb.Radius = s.Radius
+	if s.Iterations != nil {
+		sb.Iterations = *s.Iterations
+	}
+	sb.Time = s.Time
+
+	for i := range s.Particles {
+		p := &s.Particles[i]
+		n := sb.AddParticle(&p.Position, p.Mass)
+		if p.InvMass != nil {
+			if *p.InvMass < 0 {
+				return nil, fmt.Errorf("physics: scene particle %d has negative invMass", i)
+			}
+			sb.Particles[n].InvMass = *p.InvMass
+		}
+		sb.Particles[n].Velocity = p.Velocity
+		sb.Particles[n].Pinned = p.Pinned
+	}
+
+	np := len(sb.Particles)
+	valid := func(is ...int) (bool) {
+		for _, i := range is {
+			if i < 0 || i >= np {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, d := range s.Distances {
+		if !valid(d.A, d.B) {
+			return nil, ErrSceneIndex
+		}
+		n := sb.AddDistanceConstraint(d.A, d.B, d.Stiffness)
+		if d.RestLength != nil {
+			sb.Distances[n].RestLength = *d.RestLength
+		}
+	}
+	for _, b := range s.Bendings {
+		if !valid(b.A, b.V, b.B) {
+			return nil, ErrSceneIndex
+		}
+		n := sb.AddBendingConstraint(b.A, b.V, b.B, b.Stiffness)
+		if b.RestHeight != nil {
+			sb.Bendings[n].RestHeight = *b.RestHeight
+		}
+	}
+	for _, v := range s.Volumes {
+		for _, tri := range v.Triangles {
+			if !valid(tri[0], tri[1], tri[2]) {
+				return nil, ErrSceneIndex
+			}
+		}
+		n := sb.AddVolumeConstraint(v.Triangles, v.Stiffness)
+		if v.Pressure != nil {
+			sb.Volumes[n].Pressure = *v.Pressure
+		}
+		if v.RestVolume != nil {
+			sb.Volumes[n].RestVolume = *v.RestVolume
+		}
+	}
+
+	for i := range s.Cloths {
+		c := &s.Cloths[i]
+		first := len(sb.Particles)
+		cloth := NewCloth(&c.Origin, &c.U, &c.V, c.Cols, c.Rows, c.Mass, c.Stiffness)
+		sb.merge(cloth)
+		for _, p := range c.Pinned {
+			if p < 0 || first + p >= len(sb.Particles) {
+				return nil, ErrSceneIndex
+			}
+			sb.Pin(first + p)
+		}
+	}
+	for i := range s.Ropes {
+		r := &s.Ropes[i]
+		first, last := sb.AddRope(&r.From, &r.To, r.Segments, r.Mass, r.Stiffness, r.Bending)
+		if r.AttachFrom {
+			sb.Attach(first, &r.From)
+		}
+		if r.AttachTo {
+			sb.Attach(last, &r.To)
+		}
+	}
+
+	for i := range s.Colliders {
+		c, err := s.Colliders[i].collider()
+		if err != nil {
+			return nil, err
+		}
+		sb.Colliders = append(sb.Colliders, c)
+	}
+	for i := range s.Fields {
+		f, err := s.Fields[i].field()
+		if err != nil {
+			return nil, err
+		}
+		sb.Fields = append(sb.Fields, f)
+	}
+
+	return sb, nil
+}
+
+// append the particles and constraints of other, shifting its indexes
+func (sb *SoftBody) merge(other *SoftBody) {
+	off := len(sb.Particles)
+	sb.Particles = append(sb.Particles, other.Particles...)
+	for _, c := range other.Distances {
+		c.A += off
+		c.B += off
+		sb.Distances = append(sb.Distances, c)
+	}
+	for _, c := range other.Bendings {
+		c.A += off
+		c.B += off
+		c.V += off
+		sb.Bendings = append(sb.Bendings, c)
+	}
+	for _, c := range other.Volumes {
+		tris := make([][3]int, len(c.Triangles))
+		for i, tri := range c.Triangles {
+			tris[i] = [3]int{ tri[0] + off, tri[1] + off, tri[2] + off }
+		}
+		c.Triangles = tris
+		c.indices = nil
+		c.init()
+		sb.Volumes = append(sb.Volumes, c)
+	}
+}
+
+/**
+ * Describe a live SoftBody. Colliders and Fields must be of the built-in types.
+ * @method newSceneFromSoftBody
+ * @param {SoftBody} sb
+ * @return {Scene}
+ */
+func NewSceneFromSoftBody(sb *SoftBody) (*Scene, error) {
+	damping, iterations := sb.Damping, sb.Iterations
+	s := &Scene{
+		Gravity: sb.Gravity,
+		Damping: &damping,
+		Radius: sb.Radius,
+		Iterations: &iterations,
+		Time: sb.Time,
+	}
+
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		// 1 / (1 / m) != m for some m, so the exact InvMass goes along with the readable Mass
+		invMass := p.InvMass
+		sp := SceneParticle{ Position: p.Position, Velocity: p.Velocity, InvMass: &invMass, Pinned: p.Pinned }
+		if p.InvMass > 0 {
+			sp.Mass = 1 / p.InvMass
+		}
+		s.Particles = append(s.Particles, sp)
+	}
+	for _, c := range sb.Distances {
+		rest := c.RestLength
+		s.Distances = append(s.Distances, SceneDistance{ A: c.A, B: c.B, Stiffness: c.Stiffness, RestLength: &rest })
+	}
+	for _, c := range sb.Bendings {
+		rest := c.RestHeight
+		s.Bendings = append(s.Bendings, SceneBending{ A: c.A, V: c.V, B: c.B, Stiffness: c.Stiffness, RestHeight: &rest })
+	}
+	for _, c := range sb.Volumes {
+		rest, pressure := c.RestVolume, c.Pressure
+		s.Volumes = append(s.Volumes, SceneVolume{ Triangles: c.Triangles, Stiffness: c.Stiffness, Pressure: &pressure, RestVolume: &rest })
+	}
+
+	for _, c := range sb.Colliders {
+		switch c := c.(type) {
+		case *PlaneCollider:
+			s.Colliders = append(s.Colliders, SceneCollider{ Type: "plane", Normal: c.Normal.Clone(), Constant: c.Constant })
+		case *SphereCollider:
+			s.Colliders = append(s.Colliders, SceneCollider{ Type: "sphere", Center: c.Center.Clone(), Radius: c.Radius })
+		case *BoxCollider:
+			pos, rot := c.Transform.values()
+			st := &SceneTransform{ Position: pos, Quaternion: &rot }
+			s.Colliders = append(s.Colliders, SceneCollider{ Type: "box", Transform: st, HalfExtents: c.HalfExtents.Clone() })
+		default:
+			return nil, fmt.Errorf("physics: can not describe collider %T", c)
+		}
+	}
+	for _, f := range sb.Fields {
+		switch f := f.(type) {
+		case *Wind:
+			s.Fields = append(s.Fields, SceneField{ Type: "wind", Velocity: f.Velocity.Clone(), Drag: f.Drag, Turbulence: f.Turbulence, Frequency: f.Frequency })
+		case *PointGravity:
+			s.Fields = append(s.Fields, SceneField{ Type: "pointGravity", Center: f.Center.Clone(), Strength: f.Strength, MinDistance: f.MinDistance, Radius: f.Radius })
+		case *Vortex:
+			s.Fields = append(s.Fields, SceneField{ Type: "vortex", Center: f.Center.Clone(), Axis: f.Axis.Clone(), Strength: f.Strength, Pull: f.Pull, Lift: f.Lift, Radius: f.Radius })
+		case *Water:
+			s.Fields = append(s.Fields, SceneField{ Type: "water", Height: f.Height, Density: f.Density, Gravity: f.Gravity, Drag: f.Drag, Radius: f.Radius })
+		default:
+			return nil, fmt.Errorf("physics: can not describe field %T", f)
+		}
+	}
+
+	return s, nil
+}
+
+/**
+ * @method readScene
+ * @param {io.Reader} r
+ * @return {Scene}
+ */
+func ReadScene(r io.Reader) (*Scene, error) {
+	s := &Scene{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+/**
+ * @method write
+ * @param {io.Writer} w
+ */
+func (s *Scene) Write(w io.Writer) (error) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(s)
+}
+
+/**
+ * Read and build a scene file.
+ * @method loadSceneFile
+ * @param {string} path
+ * @return {SoftBody}
+ */
+func LoadSceneFile(path string) (*SoftBody, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := ReadScene(f)
+	if err != nil {
+		return nil, err
+	}
+	return s.Build()
+}
+
@@ -0,0 +1,187 @@
+package physics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testScene = `{
+	"gravity": {"x": 0, "y": -9.82, "z": 0},
+	"radius": 0.05,
+	"particles": [
+		{"position": [0, 3, 0], "mass": 0},
+		{"position": [1, 3, 0], "mass": 1}
+	],
+	"distances": [{"a": 0, "b": 1, "stiffness": 1}],
+	"cloths": [
+		{"origin": [0, 2, 0], "u": [1, 0, 0], "v": [0, 0, 1], "cols": 4, "rows": 4, "mass": 1, "stiffness": 0.9, "pinned": [0, 3]}
+	],
+	"ropes": [
+		{"from": [3, 4, 0], "to": [5, 4, 0], "segments": 4, "mass": 0.5, "stiffness": 1, "attachFrom": true}
+	],
+	"colliders": [
+		{"type": "plane", "normal": [0, 1, 0]},
+		{"type": "box", "halfExtents": [0.5, 0.5, 0.5], "transform": {"position": [0.5, 1, 0.5], "euler": {"x": 0, "y": 0.5, "z": 0, "order": "YZX"}}}
+	],
+	"fields": [
+		{"type": "wind", "velocity": [0, 0, 2], "drag": 0.1},
+		{"type": "water", "height": 0.5, "density": 1, "gravity": 9.82, "drag": 0.5, "radius": 0.05}
+	]
+}`
+
+func TestSceneBuild(t *testing.T) {
+
+	s, err := ReadScene(strings.NewReader(testScene))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := s.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(sb.Particles); n != 2 + 16 + 5 {
+		t.Error("Error building scene particles, got ", n)
+	}
+	if !sb.Particles[2].Pinned || !sb.Particles[5].Pinned || sb.Particles[3].Pinned || !sb.Particles[18].Pinned {
+		t.Error("Error pinning scene particles")
+	}
+	if sb.Damping != 0.01 || sb.Iterations != 10 {
+		t.Error("omitted settings should keep the defaults, got ", sb.Damping, sb.Iterations)
+	}
+
+	if w, ok := sb.Fields[1].(*Water); !ok || w.Height != 0.5 || w.Radius != 0.05 {
+		t.Error("Error building water field, got ", sb.Fields[1])
+	}
+
+	box := sb.Colliders[1].(*BoxCollider)
+	q := NewQuat().SetFromEuler(0, 0.5, 0, YZX)
+	if !box.Transform.Rot.AlmostEquals(q) || !box.Transform.Pos.AlmostEquals(NewVec3().Set(0.5, 1, 0.5)) {
+		t.Error("Error building box transform, got ", box.Transform.Pos, box.Transform.Rot)
+	}
+
+}
+
+func TestSceneRoundTrip(t *testing.T) {
+
+	s, _ := ReadScene(strings.NewReader(testScene))
+	sb, _ := s.Build()
+	for i := 0; i < 20; i++ {
+		sb.Step(1.0 / 60)
+	}
+
+	out, err := NewSceneFromSoftBody(sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := out.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := ReadScene(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb2, err := s2.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a, b := sb.StateHash(NewStateHash()).Sum64(), sb2.StateHash(NewStateHash()).Sum64(); a != b {
+		t.Error("written scene should rebuild the same state, got ", a, b)
+	}
+
+	for i := 0; i < 20; i++ {
+		sb.Step(1.0 / 60)
+		sb2.Step(1.0 / 60)
+	}
+	for i := range sb.Particles {
+		d := sb.Particles[i].Position.DistanceTo(&sb2.Particles[i].Position)
+		if math.IsNaN(float64(d)) || d > 1e-6 {
+			t.Fatal("rebuilt scene diverged at particle ", i, d)
+		}
+	}
+
+	sb.Iterations = 0
+	out, _ = NewSceneFromSoftBody(sb)
+	buf.Reset()
+	out.Write(&buf)
+	s2, _ = ReadScene(&buf)
+	if sb2, _ = s2.Build(); sb2.Iterations != 0 {
+		t.Error("Iterations 0 should round trip, got ", sb2.Iterations)
+	}
+
+}
+
+func TestSceneRoundTripMass(t *testing.T) {
+
+	// 15 particles of 3/15 kg, every other one given an InvMass with 1 / (1 / InvMass) != InvMass
+	cloth := NewCloth(NewVec3(), NewVec3().Set(1, 0, 0), NewVec3().Set(0, 0, 1), 5, 3, 3, 0.9)
+	cloth.Gravity.Set(0, -9.82, 0)
+	inv := Number(0.01)
+	for 1 / (1 / inv) == inv {
+		inv += 0.37
+	}
+	for i := 0; i < len(cloth.Particles); i += 2 {
+		cloth.Particles[i].InvMass = inv
+	}
+
+	out, _ := NewSceneFromSoftBody(cloth)
+	var buf bytes.Buffer
+	if err := out.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s, err := ReadScene(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := s.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range sb.Particles {
+		if sb.Particles[i].InvMass != cloth.Particles[i].InvMass {
+			t.Fatal("Error round tripping InvMass, got ", i, sb.Particles[i].InvMass, cloth.Particles[i].InvMass)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		cloth.Step(1.0 / 60)
+		sb.Step(1.0 / 60)
+	}
+	if a, b := cloth.StateHash(NewStateHash()).Sum64(), sb.StateHash(NewStateHash()).Sum64(); a != b {
+		t.Error("rewritten scene should step to the same state, got ", a, b)
+	}
+
+	s, _ = ReadScene(strings.NewReader(`{"particles": [{"position": [0, 0, 0], "invMass": -1}]}`))
+	if _, err := s.Build(); err == nil {
+		t.Error("negative invMass should fail")
+	}
+
+}
+
+func TestSceneErrors(t *testing.T) {
+
+	if _, err := ReadScene(strings.NewReader(`{"bodies": []}`)); err == nil {
+		t.Error("unknown keys should fail")
+	}
+
+	s, _ := ReadScene(strings.NewReader(`{"particles": [{"position": [0, 0, 0], "mass": 1}], "distances": [{"a": 0, "b": 1, "stiffness": 1}]}`))
+	if _, err := s.Build(); err != ErrSceneIndex {
+		t.Error("expected ErrSceneIndex, got ", err)
+	}
+
+	s, _ = ReadScene(strings.NewReader(`{"colliders": [{"type": "capsule"}]}`))
+	if _, err := s.Build(); err == nil {
+		t.Error("unknown collider type should fail")
+	}
+
+	if _, err := ReadScene(strings.NewReader(`{"colliders": [{"type": "box", "transform": {"euler": {"order": "ABC"}}}]}`)); err == nil {
+		t.Error("unknown AxisOrder should fail")
+	}
+
+}
+
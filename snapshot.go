@@ -0,0 +1,255 @@
+package physics
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+const (
+	snapshotMagic = "PSB"
+	snapshotVersion = 1
+)
+
+var (
+	ErrSnapshotFormat = errors.New("physics: not a snapshot or unsupported version")
+	ErrSnapshotShort = errors.New("physics: snapshot truncated")
+	ErrSnapshotIndex = errors.New("physics: snapshot particle index out of range")
+)
+
+// Numbers are always stored as float64, so snapshots move between float32 and float64 builds,
+// and a float32 build restores its own snapshots exactly.
+type snapshotWriter struct {
+	b []byte
+}
+
+func (w *snapshotWriter) number(n Number) {
+	w.b = binary.LittleEndian.AppendUint64(w.b, math.Float64bits(float64(n)))
+}
+
+func (w *snapshotWriter) vec3(v *Vec3) {
+	w.number(v[0])
+	w.number(v[1])
+	w.number(v[2])
+}
+
+// ints are stored as signed 32 bit, so negative settings like Iterations survive
+func (w *snapshotWriter) int(n int) {
+	w.b = binary.LittleEndian.AppendUint32(w.b, uint32(int32(n)))
+}
+
+type snapshotReader struct {
+	b []byte
+	err error
+}
+
+func (r *snapshotReader) next(n int) ([]byte) {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.b) < n {
+		r.err = ErrSnapshotShort
+		return nil
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *snapshotReader) number() (Number) {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return Number(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+}
+
+func (r *snapshotReader) vec3(v *Vec3) {
+	v[0] = r.number()
+	v[1] = r.number()
+	v[2] = r.number()
+}
+
+func (r *snapshotReader) int() (int) {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return int(int32(binary.LittleEndian.Uint32(b)))
+}
+
+// count reads a slice length, rejecting lengths the remaining data can not hold
+func (r *snapshotReader) count(itemSize int) (int) {
+	n := r.int()
+	if r.err == nil && (n < 0 || n > len(r.b) / itemSize) {
+		r.err = ErrSnapshotShort
+		return 0
+	}
+	return n
+}
+
+func (r *snapshotReader) index(n int) (int) {
+	i := r.int()
+	if r.err == nil && (i < 0 || i >= n) {
+		r.err = ErrSnapshotIndex
+	}
+	return i
+}
+
+/**
+ * Append the complete state (settings, particles and constraints) to b.
+ * Colliders and Fields are not included, they are set up by the caller.
+ * @method appendBinary
+ * @param {[]byte} b
+ * @return {[]byte}
+ */
+func (sb *SoftBody) AppendBinary(b []byte) ([]byte, error) {
+	w := &snapshotWriter{ b }
+	w.b = append(w.b, snapshotMagic...)
+	w.b = append(w.b, snapshotVersion)
+
+	w.vec3(&sb.Gravity)
+	w.number(sb.Damping)
+	w.number(sb.Radius)
+	w.int(sb.Iterations)
+	w.number(sb.Time)
+
+	w.int(len(sb.Particles))
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		w.vec3(&p.Position)
+		w.vec3(&p.Velocity)
+		w.number(p.InvMass)
+		if p.Pinned {
+			w.b = append(w.b, 1)
+		} else {
+			w.b = append(w.b, 0)
+		}
+	}
+
+	w.int(len(sb.Distances))
+	for i := range sb.Distances {
+		c := &sb.Distances[i]
+		w.int(c.A)
+		w.int(c.B)
+		w.number(c.RestLength)
+		w.number(c.Stiffness)
+	}
+
+	w.int(len(sb.Bendings))
+	for i := range sb.Bendings {
+		c := &sb.Bendings[i]
+		w.int(c.A)
+		w.int(c.B)
+		w.int(c.V)
+		w.number(c.RestHeight)
+		w.number(c.Stiffness)
+	}
+
+	w.int(len(sb.Volumes))
+	for i := range sb.Volumes {
+		c := &sb.Volumes[i]
+		w.number(c.RestVolume)
+		w.number(c.Pressure)
+		w.number(c.Stiffness)
+		w.int(len(c.Triangles))
+		for _, tri := range c.Triangles {
+			w.int(tri[0])
+			w.int(tri[1])
+			w.int(tri[2])
+		}
+	}
+
+	return w.b, nil
+}
+
+/**
+ * @method marshalBinary
+ * @return {[]byte}
+ */
+func (sb *SoftBody) MarshalBinary() ([]byte, error) {
+	return sb.AppendBinary(nil)
+}
+
+/**
+ * Restore a state written by MarshalBinary. Colliders and Fields are kept as they are.
+ * On error the SoftBody is left unchanged.
+ * @method unmarshalBinary
+ * @param {[]byte} data
+ */
+func (sb *SoftBody) UnmarshalBinary(data []byte) (error) {
+	if len(data) < len(snapshotMagic) + 1 || string(data[:len(snapshotMagic)]) != snapshotMagic || data[len(snapshotMagic)] != snapshotVersion {
+		return ErrSnapshotFormat
+	}
+	r := &snapshotReader{ b: data[len(snapshotMagic) + 1:] }
+
+	var gravity Vec3
+	r.vec3(&gravity)
+	damping := r.number()
+	radius := r.number()
+	iterations := r.int()
+	time := r.number()
+
+	np := r.count(8 * 7 + 1)
+	particles := make([]Particle, np)
+	for i := range particles {
+		p := &particles[i]
+		r.vec3(&p.Position)
+		r.vec3(&p.Velocity)
+		p.InvMass = r.number()
+		if b := r.next(1); b != nil {
+			p.Pinned = b[0] != 0
+		}
+	}
+
+	distances := make([]DistanceConstraint, r.count(4 * 2 + 8 * 2))
+	for i := range distances {
+		c := &distances[i]
+		c.A = r.index(np)
+		c.B = r.index(np)
+		c.RestLength = r.number()
+		c.Stiffness = r.number()
+	}
+
+	bendings := make([]BendingConstraint, r.count(4 * 3 + 8 * 2))
+	for i := range bendings {
+		c := &bendings[i]
+		c.A = r.index(np)
+		c.B = r.index(np)
+		c.V = r.index(np)
+		c.RestHeight = r.number()
+		c.Stiffness = r.number()
+	}
+
+	volumes := make([]VolumeConstraint, r.count(8 * 3 + 4))
+	for i := range volumes {
+		c := &volumes[i]
+		c.RestVolume = r.number()
+		c.Pressure = r.number()
+		c.Stiffness = r.number()
+		c.Triangles = make([][3]int, r.count(4 * 3))
+		for t := range c.Triangles {
+			c.Triangles[t] = [3]int{ r.index(np), r.index(np), r.index(np) }
+		}
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+
+	for i := range volumes {
+		volumes[i].init()
+	}
+	sb.Gravity = gravity
+	sb.Damping = damping
+	sb.Radius = radius
+	sb.Iterations = iterations
+	sb.Time = time
+	sb.Particles = particles
+	sb.Distances = distances
+	sb.Bendings = bendings
+	sb.Volumes = volumes
+
+	return nil
+}
+
@@ -0,0 +1,106 @@
+package physics
+
+import (
+	"testing"
+)
+
+func newSnapshotScene() (*SoftBody) {
+	sb := NewCloth(NewVec3().Set(0, 2, 0), NewVec3().Set(1, 0, 0), NewVec3().Set(0, 0, 1), 6, 6, 1, 0.8)
+	sb.Gravity.Set(0, -9.82, 0)
+	sb.Pin(0)
+	sb.Colliders = append(sb.Colliders, &PlaneCollider{ Normal: Vec3{ 0, 1, 0 } })
+
+	first := len(sb.Particles)
+	sb.AddParticle(NewVec3().Set(3, 3, 3), 1)
+	sb.AddParticle(NewVec3().Set(4, 3, 3), 1)
+	sb.AddParticle(NewVec3().Set(3, 4, 3), 1)
+	sb.AddParticle(NewVec3().Set(3, 3, 4), 1)
+	tris := [][3]int{ { 0, 2, 1 }, { 0, 1, 3 }, { 0, 3, 2 }, { 1, 2, 3 } }
+	for i := range tris {
+		for k := range tris[i] {
+			tris[i][k] += first
+		}
+	}
+	sb.AddVolumeConstraint(tris, 1)
+	return sb
+}
+
+func TestSoftBodySnapshot(t *testing.T) {
+
+	sb := newSnapshotScene()
+	for i := 0; i < 30; i++ {
+		sb.Step(1.0 / 60)
+	}
+
+	data, err := sb.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := sb.StateHash(NewStateHash()).Sum64()
+
+	for i := 0; i < 30; i++ {
+		sb.Step(1.0 / 60)
+	}
+	want := sb.StateHash(NewStateHash()).Sum64()
+
+	restored := NewSoftBody()
+	restored.Colliders = sb.Colliders
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.StateHash(NewStateHash()).Sum64(); got != before {
+		t.Error("restored state differs, got ", got, before)
+	}
+
+	for i := 0; i < 30; i++ {
+		restored.Step(1.0 / 60)
+	}
+	if got := restored.StateHash(NewStateHash()).Sum64(); got != want {
+		t.Error("replay after restore differs, got ", got, want)
+	}
+
+}
+
+func TestSoftBodySnapshotErrors(t *testing.T) {
+
+	sb := newSnapshotScene()
+	data, _ := sb.MarshalBinary()
+	want := sb.StateHash(NewStateHash()).Sum64()
+
+	if err := sb.UnmarshalBinary([]byte("nope")); err != ErrSnapshotFormat {
+		t.Error("expected ErrSnapshotFormat, got ", err)
+	}
+	if err := sb.UnmarshalBinary(data[:len(data) - 1]); err != ErrSnapshotShort {
+		t.Error("expected ErrSnapshotShort, got ", err)
+	}
+	if got := sb.StateHash(NewStateHash()).Sum64(); got != want {
+		t.Error("failed restore should not change state, got ", got, want)
+	}
+
+}
+
+func TestSoftBodySnapshotInts(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Iterations = -1
+	data, _ := sb.MarshalBinary()
+
+	restored := NewSoftBody()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Iterations != -1 {
+		t.Error("Error restoring negative Iterations, got ", restored.Iterations)
+	}
+
+	// the particle count is followed by the three empty constraint counts
+	at := len(data) - 4 * 4
+	for _, count := range [][4]byte{ { 0xff, 0xff, 0xff, 0x7f }, { 0xff, 0xff, 0xff, 0xff } } {
+		bad := append([]byte(nil), data...)
+		copy(bad[at:], count[:])
+		if err := restored.UnmarshalBinary(bad); err != ErrSnapshotShort {
+			t.Error("expected ErrSnapshotShort for count ", count, ", got ", err)
+		}
+	}
+
+}
@@ -0,0 +1,696 @@
+package physics
+
+import (
+	"math"
+)
+
+/**
+ * A point mass of a SoftBody.
+ * @class Particle
+ * @param {Vec3} Position
+ * @param {Vec3} Velocity
+ * @param {Number} InvMass Zero means infinite mass
+ * @param {bool} Pinned Pinned particles are not moved by the solver, move Position by hand to drag them
+ */
+type Particle struct {
+	Position Vec3
+	Velocity Vec3
+	InvMass Number
+	Pinned bool
+
+	predicted Vec3
+}
+
+func (p *Particle) invMass() (Number) {
+	if p.Pinned {
+		return 0
+	}
+	return p.InvMass
+}
+
+/**
+ * Keeps two particles at a fixed distance.
+ * @class DistanceConstraint
+ * @param {Number} Stiffness Between 0 and 1
+ */
+type DistanceConstraint struct {
+	A, B int
+	RestLength Number
+	Stiffness Number
+
+	stiff stiffnessCache
+}
+
+/**
+ * Triangle bending constraint: keeps V at its rest height over the centroid of A, B and V.
+ * See Kelager et al., "A Triangle Bending Constraint Model for Position-Based Dynamics".
+ * @class BendingConstraint
+ * @param {Number} Stiffness Between 0 and 1
+ */
+type BendingConstraint struct {
+	A, B, V int
+	RestHeight Number
+	Stiffness Number
+
+	stiff stiffnessCache
+}
+
+/**
+ * Keeps the volume enclosed by a closed triangle mesh.
+ * Triangles must be wound counter-clockwise seen from outside.
+ * @class VolumeConstraint
+ * @param {Number} RestVolume
+ * @param {Number} Pressure Target volume is Pressure * RestVolume
+ * @param {Number} Stiffness Between 0 and 1
+ */
+type VolumeConstraint struct {
+	Triangles [][3]int
+	RestVolume Number
+	Pressure Number
+	Stiffness Number
+
+	indices []int // unique particles of Triangles
+	slots [][3]int // Triangles as indexes into indices
+	grad []Vec3
+	stiff stiffnessCache
+}
+
+/**
+ * Something particles can not enter.
+ * @class ParticleCollider
+ */
+type ParticleCollider interface {
+	// ProjectParticle moves p out of the collider and reports if it was inside.
+	ProjectParticle(p *Vec3, radius Number) (bool)
+}
+
+/**
+ * Particle based soft body / cloth simulated with position based dynamics.
+ * Dispatches EventPreStep and EventPostStep around Step, and EventCollide once per step for every particle a collider
+ * pushes out in the first iteration, with BodyA the *Particle and BodyB the ParticleCollider.
+ * @class SoftBody
+ * @param {Vec3} Gravity
+ * @param {Number} Damping Fraction of velocity lost per second, like Body.linearDamping. Clamped to [0, 1]
+ * @param {Number} Radius Collision radius of the particles
+ * @param {int} Iterations Solver iterations per step
+ * @param {Number} Time Simulated time, advanced by Step
+ */
+type SoftBody struct {
+	EventTarget
+
+	Particles []Particle
+	Distances []DistanceConstraint
+	Bendings []BendingConstraint
+	Volumes []VolumeConstraint
+	Colliders []ParticleCollider
+	Fields []ForceField
+
+	Gravity Vec3
+	Damping Number
+	Radius Number
+	Iterations int
+	Time Number
+
+	force Vec3 // scratch for Fields, a local would escape through the interface call
+	event Event // reused for every dispatch
+}
+
+func NewSoftBody() (*SoftBody) {
+	sb := &SoftBody{
+		Damping: 0.01,
+		Iterations: 10,
+	}
+	return sb
+}
+
+/**
+ * Add a particle
+ * @method addParticle
+ * @param {Vec3} position
+ * @param {Number} mass Zero gives a static particle
+ * @return {int} index of the particle
+ */
+func (sb *SoftBody) AddParticle(position *Vec3, mass Number) (int) {
+	p := Particle{}
+	p.Position.Copy(position)
+	if mass > 0 {
+		p.InvMass = 1 / mass
+	}
+	sb.Particles = append(sb.Particles, p)
+	return len(sb.Particles) - 1
+}
+
+/**
+ * Pin a particle at its current position
+ * @method pin
+ * @param {int} i
+ */
+func (sb *SoftBody) Pin(i int) {
+	sb.Particles[i].Pinned = true
+	sb.Particles[i].Velocity.Set(0, 0, 0)
+}
+
+/**
+ * @method unpin
+ * @param {int} i
+ */
+func (sb *SoftBody) Unpin(i int) {
+	sb.Particles[i].Pinned = false
+}
+
+/**
+ * Add a distance constraint, the rest length is the current distance.
+ * @method addDistanceConstraint
+ * @return {int} index in Distances
+ */
+func (sb *SoftBody) AddDistanceConstraint(a, b int, stiffness Number) (int) {
+	c := DistanceConstraint{
+		A: a,
+		B: b,
+		RestLength: sb.Particles[a].Position.DistanceTo(&sb.Particles[b].Position),
+		Stiffness: stiffness,
+	}
+	sb.Distances = append(sb.Distances, c)
+	return len(sb.Distances) - 1
+}
+
+/**
+ * Add a bending constraint over the particle chain a - v - b, the rest shape is the current one.
+ * @method addBendingConstraint
+ * @return {int} index in Bendings
+ */
+func (sb *SoftBody) AddBendingConstraint(a, v, b int, stiffness Number) (int) {
+	var h Vec3
+	bendingHeight(&sb.Particles[a].Position, &sb.Particles[b].Position, &sb.Particles[v].Position, &h)
+	c := BendingConstraint{
+		A: a,
+		B: b,
+		V: v,
+		RestHeight: h.Length(),
+		Stiffness: stiffness,
+	}
+	sb.Bendings = append(sb.Bendings, c)
+	return len(sb.Bendings) - 1
+}
+
+/**
+ * Add a volume constraint over a closed mesh, the rest volume is the current one.
+ * @method addVolumeConstraint
+ * @param {[][3]int} triangles Counter-clockwise seen from outside
+ * @return {int} index in Volumes
+ */
+func (sb *SoftBody) AddVolumeConstraint(triangles [][3]int, stiffness Number) (int) {
+	c := VolumeConstraint{
+		Triangles: triangles,
+		RestVolume: sb.Volume(triangles),
+		Pressure: 1,
+		Stiffness: stiffness,
+	}
+	c.init()
+
+	sb.Volumes = append(sb.Volumes, c)
+	return len(sb.Volumes) - 1
+}
+
+// build the solver scratch for Triangles
+func (c *VolumeConstraint) init() {
+	c.indices = c.indices[:0]
+	c.slots = make([][3]int, len(c.Triangles))
+
+	slot := make(map[int]int)
+	for t, tri := range c.Triangles {
+		for k, i := range tri {
+			s, ok := slot[i]
+			if !ok {
+				s = len(c.indices)
+				slot[i] = s
+				c.indices = append(c.indices, i)
+			}
+			c.slots[t][k] = s
+		}
+	}
+	c.grad = make([]Vec3, len(c.indices))
+}
+
+/**
+ * Volume enclosed by a closed triangle mesh of the particles.
+ * @method volume
+ * @param {[][3]int} triangles Counter-clockwise seen from outside
+ * @return {Number}
+ */
+func (sb *SoftBody) Volume(triangles [][3]int) (Number) {
+	var vol Number
+	var c Vec3
+	for _, tri := range triangles {
+		p0, p1, p2 := &sb.Particles[tri[0]].Position, &sb.Particles[tri[1]].Position, &sb.Particles[tri[2]].Position
+		p0.Cross(p1, &c)
+		vol += c.Dot(p2)
+	}
+	return vol / 6
+}
+
+/**
+ * Advance the simulation.
+ * @method step
+ * @param {Number} dt
+ */
+func (sb *SoftBody) Step(dt Number) {
+	if dt <= 0 {
+		return
+	}
+
+	iterations := sb.Iterations
+	if iterations < 1 {
+		iterations = 1
+	}
+
+	if sb.HasAnyEventListener(EventPreStep) {
+		sb.dispatch(EventPreStep)
+	}
+
+	damping := Number(detPow(float64(1 - clamp(sb.Damping, 0, 1)), float64(dt)))
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		if p.invMass() == 0 {
+			p.Velocity.Set(0, 0, 0)
+		} else {
+			// every field sees the velocity from the start of the step, not one already kicked by gravity
+			accel := sb.Gravity
+			for _, f := range sb.Fields {
+				f.Force(&p.Position, &p.Velocity, 1 / p.InvMass, sb.Time, &sb.force)
+				addScaled(&accel, p.InvMass, &sb.force)
+			}
+			addScaled(&p.Velocity, dt, &accel)
+			p.Velocity.Scale(damping, &p.Velocity)
+		}
+		p.Position.AddScaledVector(dt, &p.Velocity, &p.predicted)
+	}
+
+	for it := 0; it < iterations; it++ {
+		for i := range sb.Distances {
+			c := &sb.Distances[i]
+			c.project(sb.Particles, c.stiff.get(c.Stiffness, iterations))
+		}
+		for i := range sb.Bendings {
+			c := &sb.Bendings[i]
+			c.project(sb.Particles, c.stiff.get(c.Stiffness, iterations))
+		}
+		for i := range sb.Volumes {
+			c := &sb.Volumes[i]
+			c.project(sb.Particles, c.stiff.get(c.Stiffness, iterations))
+		}
+		sb.collide(it == 0 && sb.HasAnyEventListener(EventCollide))
+	}
+
+	invDt := 1 / dt
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		if p.invMass() == 0 {
+			continue
+		}
+		p.predicted.VSub(&p.Position, &p.Velocity)
+		p.Velocity.Scale(invDt, &p.Velocity)
+		p.Position.Copy(&p.predicted)
+	}
+
+	sb.Time += dt
+
+	if sb.HasAnyEventListener(EventPostStep) {
+		sb.dispatch(EventPostStep)
+	}
+}
+
+func (sb *SoftBody) dispatch(typ EventType) {
+	sb.event = Event{ Type: typ }
+	sb.DispatchEvent(&sb.event, sb)
+}
+
+/**
+ * Apply an explosion impulse to all particles.
+ * @method applyExplosion
+ * @param {Explosion} e
+ */
+func (sb *SoftBody) ApplyExplosion(e *Explosion) {
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		if p.invMass() == 0 {
+			continue
+		}
+		if e.Impulse(&p.Position, &sb.force) {
+			addScaled(&p.Velocity, p.InvMass, &sb.force)
+		}
+	}
+}
+
+func (sb *SoftBody) collide(events bool) {
+	for i := range sb.Particles {
+		p := &sb.Particles[i]
+		if p.invMass() == 0 {
+			continue
+		}
+		for _, c := range sb.Colliders {
+			before := p.predicted
+			if c.ProjectParticle(&p.predicted, sb.Radius) && events {
+				sb.dispatchCollide(p, c, &before)
+			}
+		}
+	}
+}
+
+// before is where the particle was before the collider moved it out
+func (sb *SoftBody) dispatchCollide(p *Particle, c ParticleCollider, before *Vec3) {
+	e := &sb.event
+	*e = Event{ Type: EventCollide, BodyA: p, BodyB: c }
+	before.VSub(&p.predicted, &e.ContactNormal) // into the collider
+	e.ContactNormal.Normalize()
+	p.predicted.AddScaledVector(sb.Radius, &e.ContactNormal, &e.ContactPoint)
+	e.ImpactVelocity = p.Velocity.Dot(&e.ContactNormal)
+	sb.DispatchEvent(e, sb)
+}
+
+func (c *DistanceConstraint) project(ps []Particle, k Number) {
+	a, b := &ps[c.A], &ps[c.B]
+	wa, wb := a.invMass(), b.invMass()
+	w := wa + wb
+	if w == 0 {
+		return
+	}
+
+	var n Vec3
+	a.predicted.VSub(&b.predicted, &n)
+	l := n.Normalize()
+	if l == 0 {
+		return
+	}
+
+	s := (l - c.RestLength) / w * k
+	addScaled(&a.predicted, -wa * s, &n)
+	addScaled(&b.predicted, wb * s, &n)
+}
+
+func (c *BendingConstraint) project(ps []Particle, k Number) {
+	a, b, v := &ps[c.A], &ps[c.B], &ps[c.V]
+	wa, wb, wv := a.invMass(), b.invMass(), v.invMass()
+	w := wa + wb + 2 * wv
+	if w == 0 {
+		return
+	}
+
+	var h Vec3
+	bendingHeight(&a.predicted, &b.predicted, &v.predicted, &h)
+	l := h.Length()
+	if l == 0 {
+		return
+	}
+
+	f := (1 - c.RestHeight / l) * k
+	addScaled(&a.predicted, 2 * wa / w * f, &h)
+	addScaled(&b.predicted, 2 * wb / w * f, &h)
+	addScaled(&v.predicted, -4 * wv / w * f, &h)
+}
+
+func (c *VolumeConstraint) project(ps []Particle, k Number) {
+	var vol Number
+	var t Vec3
+
+	for i := range c.grad {
+		c.grad[i].Set(0, 0, 0)
+	}
+	for _, s := range c.slots {
+		p0, p1, p2 := &ps[c.indices[s[0]]].predicted, &ps[c.indices[s[1]]].predicted, &ps[c.indices[s[2]]].predicted
+		p0.Cross(p1, &t)
+		vol += t.Dot(p2)
+
+		p1.Cross(p2, &t)
+		c.grad[s[0]].VAdd(&t, &c.grad[s[0]])
+		p2.Cross(p0, &t)
+		c.grad[s[1]].VAdd(&t, &c.grad[s[1]])
+		p0.Cross(p1, &t)
+		c.grad[s[2]].VAdd(&t, &c.grad[s[2]])
+	}
+	vol /= 6
+
+	var denom Number
+	for s, i := range c.indices {
+		c.grad[s].Scale(1.0 / 6, &c.grad[s])
+		denom += Number(ps[i].invMass() * c.grad[s].LengthSquared())
+	}
+	if denom == 0 {
+		return
+	}
+
+	lambda := -(vol - Number(c.Pressure * c.RestVolume)) / denom * k
+	for s, i := range c.indices {
+		addScaled(&ps[i].predicted, lambda * ps[i].invMass(), &c.grad[s])
+	}
+}
+
+// Stiffness per solver iteration so that the result does not depend on the iteration count.
+func iterationStiffness(k Number, iterations int) (Number) {
+	k = clamp(k, 0, 1)
+	return 1 - Number(detPow(float64(1 - k), 1 / float64(iterations)))
+}
+
+// iterationStiffness of a constraint, only recomputed when Stiffness or the iteration count change
+type stiffnessCache struct {
+	stiffness Number
+	iterations int
+	k Number
+}
+
+func (c *stiffnessCache) get(stiffness Number, iterations int) (Number) {
+	if c.iterations != iterations || c.stiffness != stiffness {
+		c.stiffness, c.iterations = stiffness, iterations
+		c.k = iterationStiffness(stiffness, iterations)
+	}
+	return c.k
+}
+
+// h = v - centroid(a, b, v)
+func bendingHeight(a, b, v *Vec3, h *Vec3) {
+	for i := 0; i < 3; i++ {
+		h[i] = v[i] - (a[i] + b[i] + v[i]) / 3
+	}
+}
+
+// p += s * d, the product is rounded so it is not fused into FMA
+func addScaled(p *Vec3, s Number, d *Vec3) {
+	p[0] += Number(s * d[0])
+	p[1] += Number(s * d[1])
+	p[2] += Number(s * d[2])
+}
+
+
+/**
+ * Half space Normal . p >= Constant
+ * @class PlaneCollider
+ * @param {Vec3} Normal Unit length
+ * @param {Number} Constant
+ */
+type PlaneCollider struct {
+	Normal Vec3
+	Constant Number
+}
+
+func (c *PlaneCollider) ProjectParticle(p *Vec3, radius Number) (bool) {
+	d := c.Normal.Dot(p) - c.Constant - radius
+	if d >= 0 {
+		return false
+	}
+	addScaled(p, -d, &c.Normal)
+	return true
+}
+
+/**
+ * @class SphereCollider
+ * @param {Vec3} Center
+ * @param {Number} Radius
+ */
+type SphereCollider struct {
+	Center Vec3
+	Radius Number
+}
+
+func (c *SphereCollider) ProjectParticle(p *Vec3, radius Number) (bool) {
+	r := c.Radius + radius
+	var d Vec3
+	p.VSub(&c.Center, &d)
+	l := d.Normalize()
+	if l >= r {
+		return false
+	}
+	if l == 0 {
+		d.Set(0, 1, 0) // Make something up
+	}
+	c.Center.AddScaledVector(r, &d, p)
+	return true
+}
+
+/**
+ * Oriented box.
+ * @class BoxCollider
+ * @param {Transform} Transform Position and orientation of the box center, nil Pos and Rot are the origin and identity
+ * @param {Vec3} HalfExtents
+ */
+type BoxCollider struct {
+	Transform Transform
+	HalfExtents Vec3
+}
+
+func (c *BoxCollider) ProjectParticle(p *Vec3, radius Number) (bool) {
+	pos, rot := c.Transform.values()
+	var local Vec3
+	TransformPointToLocalFrame(&pos, &rot, p, &local)
+
+	axis := -1
+	var depth Number
+	for i := 0; i < 3; i++ {
+		d := c.HalfExtents[i] + radius - Number(math.Abs(float64(local[i])))
+		if d <= 0 {
+			return false
+		}
+		if axis < 0 || d < depth {
+			axis, depth = i, d
+		}
+	}
+
+	if local[axis] < 0 {
+		local[axis] -= depth
+	} else {
+		local[axis] += depth
+	}
+	TransformPointToWorldFrame(&pos, &rot, &local, p)
+	return true
+}
+
+
+/**
+ * Build a rectangular cloth spanning origin + s*u + t*v, s and t in [0, 1].
+ * Particles are in row major order, particle (col, row) is at index row*cols + col.
+ * @method newCloth
+ * @param {Vec3} origin
+ * @param {Vec3} u Edge along the columns
+ * @param {Vec3} v Edge along the rows
+ * @param {int} cols At least 2
+ * @param {int} rows At least 2
+ * @param {Number} mass Total mass
+ * @param {Number} stiffness Stiffness of the stretch, shear and bending constraints
+ * @return {SoftBody}
+ */
+func NewCloth(origin, u, v *Vec3, cols, rows int, mass Number, stiffness Number) (*SoftBody) {
+	sb := NewSoftBody()
+	if cols < 2 || rows < 2 {
+		return sb
+	}
+
+	pm := mass / Number(cols * rows)
+	var pos Vec3
+	for j := 0; j < rows; j++ {
+		for i := 0; i < cols; i++ {
+			origin.AddScaledVector(Number(i) / Number(cols - 1), u, &pos)
+			addScaled(&pos, Number(j) / Number(rows - 1), v)
+			sb.AddParticle(&pos, pm)
+		}
+	}
+
+	idx := func(i, j int) (int) {
+		return j * cols + i
+	}
+	for j := 0; j < rows; j++ {
+		for i := 0; i < cols; i++ {
+			if i + 1 < cols {
+				sb.AddDistanceConstraint(idx(i, j), idx(i + 1, j), stiffness)
+			}
+			if j + 1 < rows {
+				sb.AddDistanceConstraint(idx(i, j), idx(i, j + 1), stiffness)
+			}
+			if i + 1 < cols && j + 1 < rows {
+				sb.AddDistanceConstraint(idx(i, j), idx(i + 1, j + 1), stiffness)
+				sb.AddDistanceConstraint(idx(i + 1, j), idx(i, j + 1), stiffness)
+			}
+			if i + 2 < cols {
+				sb.AddBendingConstraint(idx(i, j), idx(i + 1, j), idx(i + 2, j), stiffness)
+			}
+			if j + 2 < rows {
+				sb.AddBendingConstraint(idx(i, j), idx(i, j + 1), idx(i, j + 2), stiffness)
+			}
+		}
+	}
+
+	return sb
+}
+
+
+/**
+ * Add a rope or chain of particles from one point to another, linked by distance constraints.
+ * The ends are left free, use Attach or Pin to hang them.
+ * @method addRope
+ * @param {Vec3} from
+ * @param {Vec3} to
+ * @param {int} segments Number of links, the rope has segments+1 particles
+ * @param {Number} mass Total mass
+ * @param {Number} stiffness Stiffness of the links
+ * @param {Number} bending Stiffness against bending, zero for a slack rope
+ * @return {int, int} index of the first and the last particle
+ */
+func (sb *SoftBody) AddRope(from, to *Vec3, segments int, mass Number, stiffness Number, bending Number) (int, int) {
+	if segments < 1 {
+		segments = 1
+	}
+
+	pm := mass / Number(segments + 1)
+	var pos Vec3
+	first := len(sb.Particles)
+	for i := 0; i <= segments; i++ {
+		from.Lerp(to, Number(i) / Number(segments), &pos)
+		sb.AddParticle(&pos, pm)
+	}
+	last := len(sb.Particles) - 1
+
+	for i := first; i < last; i++ {
+		sb.AddDistanceConstraint(i, i + 1, stiffness)
+		if bending > 0 && i + 2 <= last {
+			sb.AddBendingConstraint(i, i + 1, i + 2, bending)
+		}
+	}
+
+	return first, last
+}
+
+/**
+ * Pin a particle to a world point. Call it every step to follow a moving point, e.g. a crane hook.
+ * @method attach
+ * @param {int} i
+ * @param {Vec3} point
+ */
+func (sb *SoftBody) Attach(i int, point *Vec3) {
+	p := &sb.Particles[i]
+	p.Position.Copy(point)
+	p.predicted.Copy(point)
+	sb.Pin(i)
+}
+
+/**
+ * Build a rope hanging between two world points.
+ * @method newRope
+ * @param {Vec3} from
+ * @param {Vec3} to
+ * @param {int} segments
+ * @param {Number} mass Total mass
+ * @param {Number} stiffness
+ * @param {bool} attachFrom Pin the first particle at from
+ * @param {bool} attachTo Pin the last particle at to
+ * @return {SoftBody}
+ */
+func NewRope(from, to *Vec3, segments int, mass Number, stiffness Number, attachFrom bool, attachTo bool) (*SoftBody) {
+	sb := NewSoftBody()
+	first, last := sb.AddRope(from, to, segments, mass, stiffness, 0)
+	if attachFrom {
+		sb.Attach(first, from)
+	}
+	if attachTo {
+		sb.Attach(last, to)
+	}
+	return sb
+}
+
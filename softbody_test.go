@@ -0,0 +1,353 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSoftBodyDistance(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -10, 0)
+	a := sb.AddParticle(NewVec3().Set(0, 0, 0), 0)
+	b := sb.AddParticle(NewVec3().Set(1, 0, 0), 1)
+	sb.AddDistanceConstraint(a, b, 1)
+
+	for i := 0; i < 200; i++ {
+		sb.Step(1.0 / 60)
+	}
+
+	pa, pb := &sb.Particles[a].Position, &sb.Particles[b].Position
+	if !pa.IsZero() {
+		t.Error("static particle moved, got ", pa)
+	}
+	if d := pa.DistanceTo(pb); math.Abs(float64(d - 1)) > 1e-3 {
+		t.Error("Error keeping distance, got ", d)
+	}
+	if pb[1] >= 0 {
+		t.Error("particle should swing down, got ", pb)
+	}
+
+}
+
+func TestSoftBodyPin(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -10, 0)
+	a := sb.AddParticle(NewVec3().Set(0, 1, 0), 1)
+	sb.Pin(a)
+	sb.Step(1.0 / 60)
+
+	if !sb.Particles[a].Position.IsEquals(NewVec3().Set(0, 1, 0)) {
+		t.Error("pinned particle moved, got ", sb.Particles[a].Position)
+	}
+
+	sb.Unpin(a)
+	sb.Step(1.0 / 60)
+	if sb.Particles[a].Position[1] >= 1 {
+		t.Error("unpinned particle should fall, got ", sb.Particles[a].Position)
+	}
+
+}
+
+func TestSoftBodyColliders(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -10, 0)
+	sb.Radius = 0.1
+	a := sb.AddParticle(NewVec3().Set(0, 1, 0), 1)
+	b := sb.AddParticle(NewVec3().Set(5, 1, 0), 1)
+	c := sb.AddParticle(NewVec3().Set(10, 3, 0), 1)
+
+	box := &BoxCollider{
+		Transform: Transform{ Pos: NewVec3().Set(10, 1, 0), Rot: NewQuat().SetFromAxisAngle(NewVec3().Set(0, 1, 0), math.Pi / 4) },
+		HalfExtents: Vec3{ 1, 1, 1 },
+	}
+	sb.Colliders = append(sb.Colliders,
+		&PlaneCollider{ Normal: Vec3{ 0, 1, 0 }, Constant: 0 },
+		&SphereCollider{ Center: Vec3{ 5, 0, 0 }, Radius: 0.5 },
+		box,
+	)
+
+	for i := 0; i < 200; i++ {
+		sb.Step(1.0 / 60)
+	}
+
+	if y := sb.Particles[a].Position[1]; !almostEquals(y, 0.1) {
+		t.Error("particle should rest on the plane, got ", y)
+	}
+	if y := sb.Particles[b].Position[1]; !almostEquals(y, 0.6) {
+		t.Error("particle should rest on the sphere, got ", y)
+	}
+	if y := sb.Particles[c].Position[1]; !almostEquals(y, 2.1) {
+		t.Error("particle should rest on the box, got ", y)
+	}
+
+}
+
+func TestSoftBodyEvents(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -10, 0)
+	sb.Radius = 0.1
+	sb.AddParticle(NewVec3().Set(0, 0.2, 0), 1)
+	plane := &PlaneCollider{ Normal: Vec3{ 0, 1, 0 }, Constant: 0 }
+	sb.Colliders = append(sb.Colliders, plane)
+
+	var got []EventType
+	var hit Event
+	sb.AddEventListener(EventPreStep, func(e *Event) {
+		got = append(got, e.Type)
+	})
+	sb.AddEventListener(EventPostStep, func(e *Event) {
+		got = append(got, e.Type)
+	})
+	sb.AddEventListener(EventCollide, func(e *Event) {
+		got = append(got, e.Type)
+		hit = *e
+	})
+
+	sb.Step(1.0 / 60)
+	if len(got) != 2 || got[0] != EventPreStep || got[1] != EventPostStep {
+		t.Error("particle above the plane should only see step events, got ", got)
+	}
+
+	for i := 0; i < 20 && len(got) < 3; i++ {
+		got = got[:0]
+		sb.Step(1.0 / 60)
+	}
+	if len(got) != 3 || got[1] != EventCollide {
+		t.Fatal("particle falling on the plane should collide once, got ", got)
+	}
+	if hit.Target != sb || hit.BodyA != &sb.Particles[0] || hit.BodyB != plane {
+		t.Error("Error setting collide bodies, got ", hit.Target, hit.BodyA, hit.BodyB)
+	}
+	if !hit.ContactNormal.AlmostEquals(NewVec3().Set(0, -1, 0)) || !almostEquals(hit.ContactPoint[1], 0) || hit.ImpactVelocity <= 0 {
+		t.Error("Error setting collide contact, got ", hit.ContactNormal, hit.ContactPoint, hit.ImpactVelocity)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		got = got[:0]
+		sb.Step(1.0 / 60)
+	})
+	if allocs != 0 {
+		t.Error("Step with listeners should not allocate, got ", allocs)
+	}
+
+}
+
+func TestSoftBodyVolume(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.AddParticle(NewVec3().Set(0, 0, 0), 1)
+	sb.AddParticle(NewVec3().Set(1, 0, 0), 1)
+	sb.AddParticle(NewVec3().Set(0, 1, 0), 1)
+	sb.AddParticle(NewVec3().Set(0, 0, 1), 1)
+	tris := [][3]int{ { 0, 2, 1 }, { 0, 1, 3 }, { 0, 3, 2 }, { 1, 2, 3 } }
+
+	if v := sb.Volume(tris); !almostEquals(v, 1.0 / 6) {
+		t.Error("Error Calculating Volume, got ", v)
+	}
+
+	sb.AddVolumeConstraint(tris, 1)
+	sb.Particles[3].Position.Set(0, 0, 0.5)
+	sb.Step(1.0 / 60)
+
+	if v := sb.Volume(tris); math.Abs(float64(v - 1.0 / 6)) > 1e-3 {
+		t.Error("Error restoring volume, got ", v)
+	}
+
+}
+
+func TestSoftBodyClothAllocs(t *testing.T) {
+
+	cloth := NewCloth(NewVec3().Set(0, 2, 0), NewVec3().Set(1, 0, 0), NewVec3().Set(0, 0, 1), 10, 10, 1, 1)
+	cloth.Gravity.Set(0, -10, 0)
+	cloth.Pin(0)
+	cloth.Pin(9)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		cloth.Step(1.0 / 60)
+	})
+	if allocs != 0 {
+		t.Error("Step should not allocate, got ", allocs)
+	}
+
+	for _, p := range cloth.Particles {
+		for _, x := range p.Position {
+			if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+				t.Fatal("cloth blew up, got ", p.Position)
+			}
+		}
+	}
+
+}
+
+func TestSoftBodyStiffnessCache(t *testing.T) {
+
+	sb := NewSoftBody()
+	sb.AddParticle(NewVec3().Set(0, 0, 0), 1)
+	sb.AddParticle(NewVec3().Set(2, 0, 0), 1)
+	sb.AddDistanceConstraint(0, 1, 0.5)
+	sb.Particles[1].Position.Set(4, 0, 0)
+
+	c := &sb.Distances[0]
+	sb.Step(1.0 / 60)
+	if k := c.stiff.k; k != iterationStiffness(0.5, 10) {
+		t.Error("Error caching iteration stiffness, got ", k)
+	}
+
+	// changing Stiffness or Iterations between steps takes effect
+	c.Stiffness = 1
+	sb.Iterations = 3
+	sb.Step(1.0 / 60)
+	if k := c.stiff.k; k != 1 {
+		t.Error("Error updating cached stiffness, got ", k)
+	}
+
+}
+
+func TestSoftBodyDampingClamp(t *testing.T) {
+
+	for _, d := range []Number{ -1, 2 } {
+		sb := NewSoftBody()
+		sb.Gravity.Set(0, -9.82, 0)
+		sb.Damping = d
+		sb.AddParticle(NewVec3().Set(0, 1, 0), 1)
+		sb.Step(1.0 / 60)
+		if v := sb.Particles[0].Velocity; math.IsNaN(float64(v[1])) || math.IsNaN(float64(sb.Particles[0].Position[1])) {
+			t.Error("Damping out of [0, 1] should be clamped, got ", d, v)
+		}
+	}
+
+}
+
+func TestBoxColliderZeroTransform(t *testing.T) {
+
+	box := &BoxCollider{ HalfExtents: Vec3{ 1, 1, 1 } }
+	p := NewVec3().Set(0.5, 0.9, 0)
+	if !box.ProjectParticle(p, 0) || !p.AlmostEquals(NewVec3().Set(0.5, 1, 0)) {
+		t.Error("zero Transform should be the identity, got ", p)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		p.Set(0.5, 0.9, 0)
+		box.ProjectParticle(p, 0)
+	})
+	if allocs != 0 {
+		t.Error("ProjectParticle should not allocate, got ", allocs)
+	}
+
+}
+
+func TestSoftBodyRope(t *testing.T) {
+
+	from := NewVec3().Set(0, 5, 0)
+	to := NewVec3().Set(4, 5, 0)
+	rope := NewRope(from, to, 8, 1, 1, true, false)
+	rope.Gravity.Set(0, -10, 0)
+
+	if len(rope.Particles) != 9 || len(rope.Distances) != 8 {
+		t.Fatal("Error building rope, got ", len(rope.Particles), len(rope.Distances))
+	}
+
+	for i := 0; i < 600; i++ {
+		rope.Step(1.0 / 60)
+	}
+
+	end := &rope.Particles[8].Position
+	if !rope.Particles[0].Position.IsEquals(from) {
+		t.Error("attached end moved, got ", rope.Particles[0].Position)
+	}
+	if d := end.DistanceTo(from); d > 4.05 || end[1] > 2 {
+		t.Error("free end should hang below the anchor, got ", end, d)
+	}
+
+	hook := NewVec3().Set(1, 6, 0)
+	rope.Attach(0, hook)
+	rope.Step(1.0 / 60)
+	if !rope.Particles[0].Position.IsEquals(hook) {
+		t.Error("Error following attachment, got ", rope.Particles[0].Position)
+	}
+
+}
+
+// Scenario benchmarks, the rigid body scenes (pyramid stack, ragdolls) wait for Body and World.
+
+func benchmarkStep(b *testing.B, sb *SoftBody) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sb.Step(1.0 / 60)
+	}
+}
+
+func BenchmarkSoftBodyCloth(b *testing.B) {
+	cloth := NewCloth(NewVec3().Set(-2, 3, -2), NewVec3().Set(4, 0, 0), NewVec3().Set(0, 0, 4), 32, 32, 1, 0.9)
+	cloth.Gravity.Set(0, -9.82, 0)
+	cloth.Pin(0)
+	cloth.Pin(31)
+	cloth.Colliders = append(cloth.Colliders, &SphereCollider{ Center: Vec3{ 0, 1, 0 }, Radius: 1 }, &PlaneCollider{ Normal: Vec3{ 0, 1, 0 } })
+	cloth.Fields = append(cloth.Fields, &Wind{ Velocity: Vec3{ 0, 0, 3 }, Drag: 0.1 })
+	benchmarkStep(b, cloth)
+}
+
+func BenchmarkSoftBodyRopes(b *testing.B) {
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -9.82, 0)
+	for i := 0; i < 16; i++ {
+		x := Number(i)
+		first, _ := sb.AddRope(NewVec3().Set(x, 5, 0), NewVec3().Set(x, 5, 3), 16, 1, 1, 0.5)
+		sb.Pin(first)
+	}
+	tf := Transform{ Pos: NewVec3().Set(8, 2, 1.5), Rot: NewQuat().SetFromAxisAngle(NewVec3().Set(0, 1, 0), 0.4) }
+	sb.Colliders = append(sb.Colliders, &BoxCollider{ Transform: tf, HalfExtents: Vec3{ 8, 0.5, 1 } }, &PlaneCollider{ Normal: Vec3{ 0, 1, 0 } })
+	benchmarkStep(b, sb)
+}
+
+func BenchmarkSoftBodyParticles(b *testing.B) {
+	sb := NewSoftBody()
+	sb.Gravity.Set(0, -9.82, 0)
+	sb.Radius = 0.1
+	for i := 0; i < 1000; i++ {
+		sb.AddParticle(NewVec3().Set(Number(i % 10), Number(1 + i / 100), Number(i / 10 % 10)), 1)
+	}
+	for i := 0; i < 10; i++ {
+		sb.Colliders = append(sb.Colliders, &SphereCollider{ Center: Vec3{ Number(i), 0, 5 }, Radius: 0.5 })
+	}
+	sb.Colliders = append(sb.Colliders, &PlaneCollider{ Normal: Vec3{ 0, 1, 0 } })
+	benchmarkStep(b, sb)
+}
+
+
+// State hash after stepping a cloth through every collider and field type, recorded on amd64.
+// GOAMD64=v3 or arm64 builds must give the same bits, see Determinism in the README.
+func TestSoftBodyStepBits(t *testing.T) {
+
+	cloth := NewCloth(NewVec3().Set(0, 2, 0), NewVec3().Set(1, 0, 0), NewVec3().Set(0, 0, 1), 10, 10, 1, 0.8)
+	cloth.Gravity.Set(0, -9.82, 0)
+	cloth.Radius = 0.1
+	cloth.Pin(0)
+	cloth.Fields = append(cloth.Fields,
+		&Wind{ Velocity: Vec3{ 1, 0, 2 }, Drag: 0.3, Turbulence: 0.5, Frequency: 2 },
+		&Water{ Height: 0.5, Density: 1, Gravity: 9.82, Drag: 1, Radius: 0.1 },
+		&PointGravity{ Center: Vec3{ 0, 3, 0 }, Strength: 0.5, MinDistance: 0.1 },
+		&Vortex{ Center: Vec3{ 0.5, 0, 0.5 }, Axis: Vec3{ 0, 1, 0 }, Strength: 0.5, Pull: 0.1, Lift: 0.2, Radius: 2 },
+	)
+	cloth.Colliders = append(cloth.Colliders,
+		&SphereCollider{ Center: Vec3{ 0.5, 1, 0.5 }, Radius: 0.3 },
+		&BoxCollider{ Transform: Transform{ Pos: NewVec3().Set(0.2, 0.3, 0.1), Rot: NewQuat().SetFromEuler(0.3, 0.2, 0.1, XYZ) }, HalfExtents: Vec3{ 0.3, 0.2, 0.3 } },
+	)
+	for i := 0; i < 300; i++ {
+		cloth.Step(1.0 / 60)
+	}
+
+	want := uint64(0xb4a3c42ba754eb58)
+	if bitSizeOf[Number]() == 32 {
+		want = 0x0c4a271aff094ae0
+	}
+	if got := cloth.StateHash(NewStateHash()).Sum64(); got != want {
+		t.Error("stepping gives different bits, got ", got, " want ", want)
+	}
+
+}
@@ -1,11 +1,13 @@
 package physics
 
 
-type Transform struct {
-	Pos *Vec3
-	Rot *Quat
+type TransformT[T Float] struct {
+	Pos *Vec3T[T]
+	Rot *QuatT[T]
 }
 
+type Transform = TransformT[Number]
+
 /**
  * Get a global point in local transform coordinates.
  * @method pointToLocal
@@ -13,7 +15,7 @@ type Transform struct {
  * @param  {Vec3} result
  * @return {Vec3} The "result" vector object
  */
-func (tf *Transform) PointToLocal(worldPoint *Vec3, result *Vec3) (*Vec3) {
+func (tf *TransformT[T]) PointToLocal(worldPoint *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	return TransformPointToLocalFrame(tf.Pos, tf.Rot, worldPoint, result)
 }
 
@@ -25,14 +27,14 @@ func (tf *Transform) PointToLocal(worldPoint *Vec3, result *Vec3) (*Vec3) {
  * @param  {Vec3} result
  * @return {Vec3} The "result" vector object
  */
-func (tf *Transform) PointToWorld(localPoint *Vec3, result *Vec3) (*Vec3) {
+func (tf *TransformT[T]) PointToWorld(localPoint *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	return TransformPointToWorldFrame(tf.Pos, tf.Rot, localPoint, result)
 }
 
 
-func (tf *Transform) VectorToWorldFrame(localVector *Vec3, result *Vec3) (*Vec3) {
+func (tf *TransformT[T]) VectorToWorldFrame(localVector *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	if result == nil {
-		result = &Vec3{}
+		result = &Vec3T[T]{}
 	}
 	tf.Rot.VMult(localVector, result)
 	return result
@@ -47,13 +49,13 @@ func (tf *Transform) VectorToWorldFrame(localVector *Vec3, result *Vec3) (*Vec3)
  * @param {Vec3} worldPoint
  * @param {Vec3} result
  */
-func TransformPointToLocalFrame(position *Vec3, quaternion *Quat, worldPoint *Vec3, result *Vec3) (*Vec3) {
+func TransformPointToLocalFrame[T Float](position *Vec3T[T], quaternion *QuatT[T], worldPoint *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	if result == nil {
-		result = &Vec3{}
+		result = &Vec3T[T]{}
 	}
 	worldPoint.VSub(position, result)
-	tmpQuat := &Quat{}
-	quaternion.Conjugate(tmpQuat)
+	var tmpQuat QuatT[T]
+	quaternion.Conjugate(&tmpQuat)
 	tmpQuat.VMult(result, result)
 	return result
 }
@@ -67,9 +69,9 @@ func TransformPointToLocalFrame(position *Vec3, quaternion *Quat, worldPoint *Ve
  * @param {Vec3} localPoint
  * @param {Vec3} result
  */
-func TransformPointToWorldFrame(position *Vec3, quaternion *Quat, localPoint *Vec3, result *Vec3) (*Vec3) {
+func TransformPointToWorldFrame[T Float](position *Vec3T[T], quaternion *QuatT[T], localPoint *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	if result == nil {
-		result = &Vec3{}
+		result = &Vec3T[T]{}
 	}
 	quaternion.VMult(localPoint, result)
 	result.VAdd(position, result)
@@ -77,18 +79,18 @@ func TransformPointToWorldFrame(position *Vec3, quaternion *Quat, localPoint *Ve
 }
 
 
-func TransformVectorToWorldFrame(quaternion *Quat, localVector *Vec3, result *Vec3) (*Vec3) {
+func TransformVectorToWorldFrame[T Float](quaternion *QuatT[T], localVector *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	if result == nil {
-		result = &Vec3{}
+		result = &Vec3T[T]{}
 	}
 	quaternion.VMult(localVector, result)
 	return result
 }
 
 
-func TransformVectorToLocalFrame(position *Vec3, quaternion *Quat, worldVector *Vec3, result *Vec3) (*Vec3) {
+func TransformVectorToLocalFrame[T Float](position *Vec3T[T], quaternion *QuatT[T], worldVector *Vec3T[T], result *Vec3T[T]) (*Vec3T[T]) {
 	if result == nil {
-		result = &Vec3{}
+		result = &Vec3T[T]{}
 	}
 	quaternion[3] *= -1
 	quaternion.VMult(worldVector, result)
@@ -0,0 +1,194 @@
+package physics
+
+import (
+	"math"
+)
+
+// Value semantic versions of the Vec3, Quat and Transform operations.
+// They take and return values so the compiler can inline them and keep the results on the stack:
+//	c := a.Add(b).MulScalar(0.5)
+//	w := q.Mul(r).Rotate(v)
+
+/**
+ * Vector addition
+ * @method add
+ * @param {Vec3} u
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) Add(u Vec3T[T]) (Vec3T[T]) {
+	return Vec3T[T]{ v[0] + u[0], v[1] + u[1], v[2] + u[2] }
+}
+
+/**
+ * Vector subtraction
+ * @method sub
+ * @param {Vec3} u
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) Sub(u Vec3T[T]) (Vec3T[T]) {
+	return Vec3T[T]{ v[0] - u[0], v[1] - u[1], v[2] - u[2] }
+}
+
+/**
+ * Multiply the vector with another vector, component-wise.
+ * @method mul
+ * @param {Vec3} u
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) Mul(u Vec3T[T]) (Vec3T[T]) {
+	return Vec3T[T]{ v[0] * u[0], v[1] * u[1], v[2] * u[2] }
+}
+
+/**
+ * Multiply all the components of the vector with a scalar.
+ * @method mulScalar
+ * @param {Number} s
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) MulScalar(s T) (Vec3T[T]) {
+	return Vec3T[T]{ v[0] * s, v[1] * s, v[2] * s }
+}
+
+/**
+ * v + s * u
+ * @method addScaled
+ * @param {Number} s
+ * @param {Vec3} u
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) AddScaled(s T, u Vec3T[T]) (Vec3T[T]) {
+	return Vec3T[T]{ v[0] + T(s * u[0]), v[1] + T(s * u[1]), v[2] + T(s * u[2]) }
+}
+
+/**
+ * @method neg
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) Neg() (Vec3T[T]) {
+	return Vec3T[T]{ -v[0], -v[1], -v[2] }
+}
+
+/**
+ * Vector cross product
+ * @method crossProduct
+ * @param {Vec3} u
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) CrossProduct(u Vec3T[T]) (Vec3T[T]) {
+	return Vec3T[T]{
+		T(v[1] * u[2]) - T(v[2] * u[1]),
+		T(v[2] * u[0]) - T(v[0] * u[2]),
+		T(v[0] * u[1]) - T(v[1] * u[0]),
+	}
+}
+
+/**
+ * Vector dot product
+ * @method dotProduct
+ * @param {Vec3} u
+ * @return {Number}
+ */
+func (v Vec3T[T]) DotProduct(u Vec3T[T]) (T) {
+	return T(v[0] * u[0]) + T(v[1] * u[1]) + T(v[2] * u[2])
+}
+
+/**
+ * @method len
+ * @return {Number}
+ */
+func (v Vec3T[T]) Len() (T) {
+	return T(math.Sqrt(float64(T(v[0] * v[0]) + T(v[1] * v[1]) + T(v[2] * v[2]))))
+}
+
+/**
+ * Unit vector in the same direction, { 1, 0, 0 } for a zero vector like Unit.
+ * @method normalized
+ * @return {Vec3}
+ */
+func (v Vec3T[T]) Normalized() (Vec3T[T]) {
+	n := v.Len()
+	if n > 0.0 {
+		return v.MulScalar(1 / n)
+	}
+	return Vec3T[T]{ 1, 0, 0 }
+}
+
+/**
+ * Quaternion multiplication
+ * @method mul
+ * @param {Quaternion} r
+ * @return {Quaternion}
+ */
+func (q QuatT[T]) Mul(r QuatT[T]) (QuatT[T]) {
+	ax, ay, az, aw := q[0], q[1], q[2], q[3]
+	bx, by, bz, bw := r[0], r[1], r[2], r[3]
+	return QuatT[T]{
+		T(ax * bw) + T(aw * bx) + T(ay * bz) - T(az * by),
+		T(ay * bw) + T(aw * by) + T(az * bx) - T(ax * bz),
+		T(az * bw) + T(aw * bz) + T(ax * by) - T(ay * bx),
+		T(aw * bw) - T(ax * bx) - T(ay * by) - T(az * bz),
+	}
+}
+
+/**
+ * Rotate a vector by the quaternion, same as VMult.
+ * @method rotate
+ * @param {Vec3} v
+ * @return {Vec3}
+ */
+func (q QuatT[T]) Rotate(v Vec3T[T]) (Vec3T[T]) {
+	x, y, z := v[0], v[1], v[2]
+	qx, qy, qz, qw := q[0], q[1], q[2], q[3]
+
+	// q*v, every product rounded on its own like VMult
+	ix :=  T(qw * x) + T(qy * z) - T(qz * y)
+	iy :=  T(qw * y) + T(qz * x) - T(qx * z)
+	iz :=  T(qw * z) + T(qx * y) - T(qy * x)
+	iw := -T(qx * x) - T(qy * y) - T(qz * z)
+
+	return Vec3T[T]{
+		T(ix * qw) + T(iw * -qx) + T(iy * -qz) - T(iz * -qy),
+		T(iy * qw) + T(iw * -qy) + T(iz * -qx) - T(ix * -qz),
+		T(iz * qw) + T(iw * -qz) + T(ix * -qy) - T(iy * -qx),
+	}
+}
+
+/**
+ * @method conj
+ * @return {Quaternion}
+ */
+func (q QuatT[T]) Conj() (QuatT[T]) {
+	return QuatT[T]{ -q[0], -q[1], -q[2], q[3] }
+}
+
+/**
+ * Unit quaternion in the same direction, zero for a zero quaternion like Normalize.
+ * @method normalized
+ * @return {Quaternion}
+ */
+func (q QuatT[T]) Normalized() (QuatT[T]) {
+	q.Normalize()
+	return q
+}
+
+/**
+ * Transform a point from local to world frame, same as PointToWorld. Nil Pos and Rot are the origin and identity.
+ * @method apply
+ * @param {Vec3} p
+ * @return {Vec3}
+ */
+func (tf TransformT[T]) Apply(p Vec3T[T]) (Vec3T[T]) {
+	pos, rot := tf.values()
+	return rot.Rotate(p).Add(pos)
+}
+
+/**
+ * Transform a point from world to local frame, same as PointToLocal. Nil Pos and Rot are the origin and identity.
+ * @method applyInverse
+ * @param {Vec3} p
+ * @return {Vec3}
+ */
+func (tf TransformT[T]) ApplyInverse(p Vec3T[T]) (Vec3T[T]) {
+	pos, rot := tf.values()
+	return rot.Conj().Rotate(p.Sub(pos))
+}
@@ -0,0 +1,123 @@
+package physics
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomVec3(r *rand.Rand) (Vec3) {
+	return Vec3{ Number(r.Float64() * 4 - 2), Number(r.Float64() * 4 - 2), Number(r.Float64() * 4 - 2) }
+}
+
+func randomQuat(r *rand.Rand) (Quat) {
+	axis := randomVec3(r)
+	q := Quat{}
+	q.SetFromAxisAngle(axis.Unit(nil), Number(r.Float64() * 6))
+	return q
+}
+
+func vecEquals(got Vec3, want *Vec3) (bool) {
+	return got.AlmostEquals(want)
+}
+
+func quatEquals(got Quat, want *Quat) (bool) {
+	return got.AlmostEquals(want)
+}
+
+func TestValueVec3(t *testing.T) {
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		a, b := randomVec3(r), randomVec3(r)
+		s := Number(r.Float64() * 3)
+
+		if w := a.VAdd(&b, nil); !vecEquals(a.Add(b), w) {
+			t.Error("Add differs from VAdd, got ", a.Add(b), w)
+		}
+		if w := a.VSub(&b, nil); !vecEquals(a.Sub(b), w) {
+			t.Error("Sub differs from VSub, got ", a.Sub(b), w)
+		}
+		if w := a.VMul(&b, nil); !vecEquals(a.Mul(b), w) {
+			t.Error("Mul differs from VMul, got ", a.Mul(b), w)
+		}
+		if w := a.Scale(s, nil); !vecEquals(a.MulScalar(s), w) {
+			t.Error("MulScalar differs from Scale, got ", a.MulScalar(s), w)
+		}
+		if w := a.AddScaledVector(s, &b, nil); !vecEquals(a.AddScaled(s, b), w) {
+			t.Error("AddScaled differs from AddScaledVector, got ", a.AddScaled(s, b), w)
+		}
+		if w := a.Negate(nil); !vecEquals(a.Neg(), w) {
+			t.Error("Neg differs from Negate, got ", a.Neg(), w)
+		}
+		if w := a.Cross(&b, nil); !vecEquals(a.CrossProduct(b), w) {
+			t.Error("CrossProduct differs from Cross, got ", a.CrossProduct(b), w)
+		}
+		if !almostEquals(a.DotProduct(b), a.Dot(&b)) || !almostEquals(a.Len(), a.Length()) {
+			t.Error("DotProduct or Len differs from Dot or Length")
+		}
+		if w := a.Unit(nil); !vecEquals(a.Normalized(), w) {
+			t.Error("Normalized differs from Unit, got ", a.Normalized(), w)
+		}
+	}
+
+	if z := (Vec3{}).Normalized(); !z.IsEquals(NewVec3().Set(1, 0, 0)) {
+		t.Error("Normalized of zero should be { 1, 0, 0 }, got ", z)
+	}
+
+}
+
+func TestValueQuat(t *testing.T) {
+
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		q, p := randomQuat(r), randomQuat(r)
+		v, x := randomVec3(r), randomVec3(r)
+
+		if w := q.Mult(&p, nil); !quatEquals(q.Mul(p), w) {
+			t.Error("Mul differs from Mult, got ", q.Mul(p), w)
+		}
+		if w := q.VMult(&v, nil); !vecEquals(q.Rotate(v), w) {
+			t.Error("Rotate differs from VMult, got ", q.Rotate(v), w)
+		}
+		if w := q.Conjugate(nil); !quatEquals(q.Conj(), w) {
+			t.Error("Conj differs from Conjugate, got ", q.Conj(), w)
+		}
+
+		u := Quat{ q[0] * 3, q[1] * 3, q[2] * 3, q[3] * 3 }
+		if n := u.Normalized(); !quatEquals(n, &q) || u[3] != q[3] * 3 {
+			t.Error("Normalized should not change the receiver, got ", n, u)
+		}
+
+		tf := Transform{ Pos: &v, Rot: &q }
+		if w := tf.PointToWorld(&v, nil); !vecEquals(tf.Apply(v), w) {
+			t.Error("Apply differs from PointToWorld, got ", tf.Apply(v), w)
+		}
+		if w := tf.PointToLocal(&x, nil); !vecEquals(tf.ApplyInverse(x), w) {
+			t.Error("ApplyInverse differs from PointToLocal, got ", tf.ApplyInverse(x), w)
+		}
+	}
+
+	var zero Transform
+	v := Vec3{ 1, 2, 3 }
+	if !vecEquals(zero.Apply(v), &v) || !vecEquals(zero.ApplyInverse(v), &v) {
+		t.Error("zero Transform should be the identity, got ", zero.Apply(v), zero.ApplyInverse(v))
+	}
+
+}
+
+func TestValueAllocs(t *testing.T) {
+
+	a, b := Vec3{ 1, 2, 3 }, Vec3{ -1, 0.5, 2 }
+	q := Quat{}
+	q.SetFromAxisAngle(NewVec3().Set(0, 1, 0), 0.5)
+	tf := Transform{ Pos: &a, Rot: &q }
+
+	allocs := testing.AllocsPerRun(100, func() {
+		benchVec3 = q.Mul(q.Conj()).Rotate(a.Add(b).CrossProduct(b).Normalized())
+		benchVec3 = tf.ApplyInverse(tf.Apply(benchVec3))
+	})
+	if allocs != 0 {
+		t.Error("value API should not allocate, got ", allocs)
+	}
+
+}
@@ -4,7 +4,9 @@ import (
 	"math"
 )
 
-type Vec3 [3]Number // order: x, y, z
+type Vec3T[T Float] [3]T // order: x, y, z
+
+type Vec3 = Vec3T[Number]
 
 /*func NewVec3(x, y, z Number) (*Vec3) {
 	v := &Vec3{ x, y, z }
@@ -16,6 +18,11 @@ func NewVec3() (*Vec3) {
 	return v
 }
 
+func NewVec3T[T Float]() (*Vec3T[T]) {
+	v := &Vec3T[T]{ 0, 0, 0 }
+	return v
+}
+
 /**
  * Set the vectors' 3 elements
  * @method set
@@ -24,7 +31,7 @@ func NewVec3() (*Vec3) {
  * @param {Number} z
  * @return Vec3
  */
-func (v *Vec3) Set(x, y, z Number) (*Vec3) {
+func (v *Vec3T[T]) Set(x, y, z T) (*Vec3T[T]) {
 	v[0], v[1], v[2] = x, y, z
 	return v
 }
@@ -34,8 +41,8 @@ func (v *Vec3) Set(x, y, z Number) (*Vec3) {
  * @method clone
  * @return {Vec3}
  */
-func (v *Vec3) Clone() (*Vec3) {
-	return &Vec3{ v[0], v[1], v[2] }
+func (v *Vec3T[T]) Clone() (*Vec3T[T]) {
+	return &Vec3T[T]{ v[0], v[1], v[2] }
 }
 
 /**
@@ -44,7 +51,7 @@ func (v *Vec3) Clone() (*Vec3) {
  * @param {Vec3} source
  * @return {Vec3} this
  */
-func (v *Vec3) Copy(source *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Copy(source *Vec3T[T]) (*Vec3T[T]) {
 	v[0], v[1], v[2] = source[0], source[1], source[2]
 	return v
 };
@@ -54,7 +61,7 @@ func (v *Vec3) Copy(source *Vec3) (*Vec3) {
  * @method IsZero
  * @return bool
  */
-func (v *Vec3) IsZero() (bool) {
+func (v *Vec3T[T]) IsZero() (bool) {
 	return v[0] == 0 && v[1] == 0 && v[2] == 0
 }
 
@@ -65,9 +72,9 @@ func (v *Vec3) IsZero() (bool) {
  * @param  {Number}  precision Set to zero for exact comparisons
  * @return {Boolean}
  */
-func (v *Vec3) IsAntiparallelTo(v1 *Vec3) (bool) {
-	antip_neg := NewVec3()
-	v.Negate(antip_neg)
+func (v *Vec3T[T]) IsAntiparallelTo(v1 *Vec3T[T]) (bool) {
+	var antip_neg Vec3T[T]
+	v.Negate(&antip_neg)
 	return antip_neg.AlmostEquals(v1)
 }
 
@@ -77,7 +84,7 @@ func (v *Vec3) IsAntiparallelTo(v1 *Vec3) (bool) {
  * @param {Vec3} v
  * @return bool
  */
-func (v *Vec3) IsEquals(v1 *Vec3) (bool) {
+func (v *Vec3T[T]) IsEquals(v1 *Vec3T[T]) (bool) {
 	if (v[0] == v1[0]) && (v[1] == v1[1]) && (v[2] == v1[2]) {
 		return true
 	}
@@ -90,7 +97,7 @@ func (v *Vec3) IsEquals(v1 *Vec3) (bool) {
  * @param {Vec3} v
  * @return bool
  */
-func (v *Vec3) AlmostEquals(v1 *Vec3) (bool) {
+func (v *Vec3T[T]) AlmostEquals(v1 *Vec3T[T]) (bool) {
 	if almostEquals(v[0], v1[0]) && almostEquals(v[1], v1[1]) && almostEquals(v[2], v1[2]) {
 		return true
 	}
@@ -102,7 +109,7 @@ func (v *Vec3) AlmostEquals(v1 *Vec3) (bool) {
  * @method almostZero
  * @param {Number} precision
  */
-func (v *Vec3) AlmostZero() (bool) {
+func (v *Vec3T[T]) AlmostZero() (bool) {
 	if almostZero(v[0]) && almostZero(v[1]) && almostZero(v[2]) {
 		return true
 	}
@@ -116,8 +123,8 @@ func (v *Vec3) AlmostZero() (bool) {
  * @param {Vec3} v
  * @return {Number}
  */
-func (v *Vec3) Dot(v1 *Vec3) (Number) {
-	return v[0] * v1[0] + v[1] * v1[1] + v[2] * v1[2]
+func (v *Vec3T[T]) Dot(v1 *Vec3T[T]) (T) {
+	return T(v[0] * v1[0]) + T(v[1] * v1[1]) + T(v[2] * v1[2])
 }
 
 /**
@@ -125,7 +132,7 @@ func (v *Vec3) Dot(v1 *Vec3) (Number) {
  * @method LengthSquared
  * @return {Number}
  */
-func (v *Vec3) LengthSquared() (Number) {
+func (v *Vec3T[T]) LengthSquared() (T) {
 	return v.Dot(v)
 }
 
@@ -136,9 +143,9 @@ func (v *Vec3) LengthSquared() (Number) {
  * @param {Vec3} target Optional target to save in
  * @return {Vec3}
  */
-func (v *Vec3) Negate(target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Negate(target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 	target[0] = -v[0]
 	target[1] = -v[1]
@@ -155,9 +162,9 @@ func (v *Vec3) Negate(target *Vec3) (*Vec3) {
  * @param {Vec3} target Optional.
  * @return {Vec3}
  */
-func (v *Vec3) VAdd(v1 *Vec3, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) VAdd(v1 *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
 	target[0] = v[0] + v1[0]
@@ -174,9 +181,9 @@ func (v *Vec3) VAdd(v1 *Vec3, target *Vec3) (*Vec3) {
  * @param {Vec3} target Optional. Target to save in.
  * @return {Vec3}
  */
-func (v *Vec3) VSub(v1 *Vec3, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) VSub(v1 *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
 	target[0] = v[0] - v1[0]
@@ -193,17 +200,17 @@ func (v *Vec3) VSub(v1 *Vec3, target *Vec3) (*Vec3) {
  * @param {Vec3} target Optional. Target to save in.
  * @return {Vec3}
  */
-func (v *Vec3) Cross(v1 *Vec3, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Cross(v1 *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	vx, vy, vz := v1[0], v1[1], v1[2]
 	x, y, z := v[0], v[1], v[2]
 
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
-	target[0] = (y * vz) - (z * vy)
-	target[1] = (z * vx) - (x * vz)
-	target[2] = (x * vy) - (y * vx)
+	target[0] = T(y * vz) - T(z * vy)
+	target[1] = T(z * vx) - T(x * vz)
+	target[2] = T(x * vy) - T(y * vx)
 
 	return target
 }
@@ -215,11 +222,11 @@ func (v *Vec3) Cross(v1 *Vec3, target *Vec3) (*Vec3) {
  * @return {Number}
  * @deprecated Use .length() instead
  */
-func (v *Vec3) Norm() (Number) {
+func (v *Vec3T[T]) Norm() (T) {
 	x, y, z := v[0], v[1], v[2]
-	n := math.Sqrt(float64(x*x + y*y + z*z))
+	n := math.Sqrt(float64(T(x*x) + T(y*y) + T(z*z)))
 
-	return Number(n)
+	return T(n)
 }
 
 /**
@@ -227,7 +234,7 @@ func (v *Vec3) Norm() (Number) {
  * @method Length
  * @return {Number}
  */
-func (v *Vec3) Length() (Number) {
+func (v *Vec3T[T]) Length() (T) {
 	return v.Norm()
 }
 
@@ -236,7 +243,7 @@ func (v *Vec3) Length() (Number) {
  * @method Normalize
  * @return {Number} Returns the norm of the vector
  */
-func (v *Vec3) Normalize() (Number) {
+func (v *Vec3T[T]) Normalize() (T) {
 
 	n := v.Norm()
 	if n > 0.0 {
@@ -259,9 +266,9 @@ func (v *Vec3) Normalize() (Number) {
  * @param {Vec3} target Optional target to save in
  * @return {Vec3} Returns the unit vector
  */
-func (v *Vec3) Unit(target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Unit(target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{1, 0, 0}
+		target = &Vec3T[T]{1, 0, 0}
 	}
 
 	n := v.Norm()
@@ -282,11 +289,11 @@ func (v *Vec3) Unit(target *Vec3) (*Vec3) {
  * @param  {Vec3} p
  * @return {Number}
  */
-func (v *Vec3) DistanceSquared(p *Vec3) (Number) {
+func (v *Vec3T[T]) DistanceSquared(p *Vec3T[T]) (T) {
 	x, y, z := v[0], v[1], v[2]
 	px, py, pz := p[0], p[1], p[2]
 
-	return (px-x)*(px-x) + (py-y)*(py-y) + (pz-z)*(pz-z)
+	return T((px-x)*(px-x)) + T((py-y)*(py-y)) + T((pz-z)*(pz-z))
 }
 
 /**
@@ -295,9 +302,9 @@ func (v *Vec3) DistanceSquared(p *Vec3) (Number) {
  * @param  {Vec3} p
  * @return {Number}
  */
-func (v *Vec3) DistanceTo(p *Vec3) (Number) {
+func (v *Vec3T[T]) DistanceTo(p *Vec3T[T]) (T) {
 	diSq := v.DistanceSquared(p)
-	return Number(math.Sqrt(float64(diSq)))
+	return T(math.Sqrt(float64(diSq)))
 }
 
 
@@ -308,9 +315,9 @@ func (v *Vec3) DistanceTo(p *Vec3) (Number) {
  * @param {Vec3} target The vector to save the result in.
  * @return {Vec3}
  */
-func (v *Vec3) Scale(scalar Number, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Scale(scalar T, target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
 	target[0] = v[0] * scalar
@@ -328,9 +335,9 @@ func (v *Vec3) Scale(scalar Number, target *Vec3) (*Vec3) {
  * @param {Vec3} target The vector to save the result in.
  * @return {Vec3}
  */
-func (v *Vec3) VMul(vector *Vec3, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) VMul(vector *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
 	target[0] = vector[0] * v[0]
@@ -348,14 +355,16 @@ func (v *Vec3) VMul(vector *Vec3, target *Vec3) (*Vec3) {
  * @param {Vec3} target The vector to save the result in.
  * @return {Vec3}
  */
-func (v *Vec3) AddScaledVector(scalar Number, vector *Vec3, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) AddScaledVector(scalar T, vector *Vec3T[T], target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
-	// target = this + scalar * vector
-	scaled := vector.Scale(scalar, target)
-	v.VAdd(scaled, target)
+	// target = this + scalar * vector, safe when target is this or vector.
+	// The products are rounded on their own so they are not fused into FMA, see Determinism in README.
+	target[0] = v[0] + T(scalar * vector[0])
+	target[1] = v[1] + T(scalar * vector[1])
+	target[2] = v[2] + T(scalar * vector[2])
 
 	return target
 }
@@ -367,15 +376,15 @@ func (v *Vec3) AddScaledVector(scalar Number, vector *Vec3, target *Vec3) (*Vec3
  * @param {Number} t A number between 0 and 1. 0 will make this function return u, and 1 will make it return v. Numbers in between will generate a vector in between them.
  * @param {Vec3} target
  */
-func (v *Vec3) Lerp(v1 *Vec3, t Number, target *Vec3) (*Vec3) {
+func (v *Vec3T[T]) Lerp(v1 *Vec3T[T], t T, target *Vec3T[T]) (*Vec3T[T]) {
 	if target == nil {
-		target = &Vec3{}
+		target = &Vec3T[T]{}
 	}
 
 	x, y, z := v[0], v[1], v[2]
-	target[0] = x + (v1[0]-x)*t
-	target[1] = y + (v1[1]-y)*t
-	target[2] = z + (v1[2]-z)*t
+	target[0] = x + T((v1[0]-x)*t)
+	target[1] = y + T((v1[1]-y)*t)
+	target[2] = z + T((v1[2]-z)*t)
 
 	return target
 }
@@ -386,18 +395,18 @@ func (v *Vec3) Lerp(v1 *Vec3, t Number, target *Vec3) (*Vec3) {
  * @param {Vec3} t1 Vector object to save the first tangent in
  * @param {Vec3} t2 Vector object to save the second tangent in
  */
-func (v *Vec3) Tangents(t1 *Vec3, t2 *Vec3) {
+func (v *Vec3T[T]) Tangents(t1 *Vec3T[T], t2 *Vec3T[T]) {
 	norm := v.Norm()
 	if norm > 0.0 {
 		inorm := 1 / norm
-		n := v.Scale(inorm, nil)
-		randVec := NewVec3()
+		var n, randVec Vec3T[T]
+		v.Scale(inorm, &n)
 		if(math.Abs(float64(n[0])) < 0.9){
 			randVec.Set(1, 0, 0)
 		} else {
 			randVec.Set(0, 1, 0)
 		}
-		n.Cross(randVec, t1)
+		n.Cross(&randVec, t1)
 		n.Cross(t1, t2)
 	} else {
 		// The normal length is zero, make something up
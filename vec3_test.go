@@ -99,4 +99,47 @@ func TestVec3IsAntiparallelTo(t *testing.T) {
 
 }
 
+func TestVec3AddScaledVectorAlias(t *testing.T) {
+
+	v := NewVec3().Set(1, 2, 3)
+	v.AddScaledVector(2, NewVec3().Set(1, 1, 1), v)
+	if !v.IsEquals(NewVec3().Set(3, 4, 5)) {
+		t.Error("Error AddScaledVector into itself, got ", v)
+	}
+
+	v.Set(1, 2, 3)
+	v.AddScaledVector(2, v, v)
+	if !v.IsEquals(NewVec3().Set(3, 6, 9)) {
+		t.Error("Error AddScaledVector of itself, got ", v)
+	}
+
+}
+
+var benchVec3 Vec3
+var benchNumber Number
+
+func BenchmarkVec3Normalize(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchVec3.Set(1, 2, 3)
+		benchNumber = benchVec3.Normalize()
+	}
+}
+
+func BenchmarkVec3Cross(b *testing.B) {
+	b.ReportAllocs()
+	u, v := NewVec3().Set(1, 2, 3), NewVec3().Set(-3, 1, 2)
+	for i := 0; i < b.N; i++ {
+		u.Cross(v, &benchVec3)
+	}
+}
+
+func BenchmarkVec3Tangents(b *testing.B) {
+	b.ReportAllocs()
+	n := NewVec3().Set(1, 2, 3)
+	var t1 Vec3
+	for i := 0; i < b.N; i++ {
+		n.Tangents(&t1, &benchVec3)
+	}
+}
 